@@ -4,6 +4,8 @@ package tree
 type BinaryNode[T any] struct {
 	node        *Node[T]
 	left, right *BinaryNode[T]
+	// less 二叉搜索树的比较函数, 为nil时为普通二叉树
+	less func(a, b T) bool
 }
 
 // NewBinaryTree 新建二叉树
@@ -35,57 +37,188 @@ func NewBinaryTree[T any](list ...T) *BinaryNode[T] {
 
 // DLR 先序遍历
 func (bn *BinaryNode[T]) DLR(fn func(T)) {
-	if bn.node == nil {
-		return
-	}
+	bn.Traverse(DLROrder, ignoreDepth(fn))
+}
 
-	fn(bn.node.data)
+// LDR 中序遍历
+func (bn *BinaryNode[T]) LDR(fn func(T)) {
+	bn.Traverse(LDROrder, ignoreDepth(fn))
+}
 
-	if bn.left != nil {
-		bn.left.DLR(fn)
-	}
+// LRD 后序遍历
+func (bn *BinaryNode[T]) LRD(fn func(T)) {
+	bn.Traverse(LRDOrder, ignoreDepth(fn))
+}
 
-	if bn.right != nil {
-		bn.right.DLR(fn)
-	}
+// BFS 层序遍历, fn返回false时停止遍历
+func (bn *BinaryNode[T]) BFS(fn func(T) bool) {
+	bn.Traverse(LevelOrder, func(t T, _ int) bool {
+		return fn(t)
+	})
 }
 
-// LDR 中序遍历
-func (bn *BinaryNode[T]) LDR(fn func(T)) {
-	if bn.node == nil {
-		return
-	}
+// Levels 按层获取所有数据
+func (bn *BinaryNode[T]) Levels() [][]T {
+	var levels [][]T
+	bn.Traverse(LevelOrder, func(t T, depth int) bool {
+		if depth == len(levels) {
+			levels = append(levels, nil)
+		}
+		levels[depth] = append(levels[depth], t)
+		return true
+	})
+
+	return levels
+}
 
-	if bn.left != nil {
-		bn.left.LDR(fn)
+func ignoreDepth[T any](fn func(T)) func(T, int) bool {
+	return func(t T, _ int) bool {
+		fn(t)
+		return true
 	}
+}
 
-	fn(bn.node.data)
+// Order 遍历顺序
+type Order uint8
+
+const (
+	// DLROrder 先序
+	DLROrder Order = iota
+	// LDROrder 中序
+	LDROrder
+	// LRDOrder 后序
+	LRDOrder
+	// LevelOrder 层序
+	LevelOrder
+)
+
+// binaryFrame 遍历时栈或队列中的元素
+type binaryFrame[T any] struct {
+	bn    *BinaryNode[T]
+	depth int
+	// expanded 后序遍历时子节点是否已入栈
+	expanded bool
+}
 
-	if bn.right != nil {
-		bn.right.LDR(fn)
-	}
+// Traverse 按指定顺序非递归遍历, depth为节点深度(根节点为0), fn返回false时停止遍历并返回false
+func (bn *BinaryNode[T]) Traverse(order Order, fn func(t T, depth int) bool) bool {
+	return bn.traverse(order, func(n *BinaryNode[T], depth int) bool {
+		return fn(n.node.data, depth)
+	})
 }
 
-// LRD 后序遍历
-func (bn *BinaryNode[T]) LRD(fn func(T)) {
+// traverse 按指定顺序非递归遍历节点
+func (bn *BinaryNode[T]) traverse(order Order, fn func(n *BinaryNode[T], depth int) bool) bool {
 	if bn.node == nil {
-		return
-	}
-
-	if bn.left != nil {
-		bn.left.LRD(fn)
+		return true
 	}
 
-	if bn.right != nil {
-		bn.right.LRD(fn)
+	switch order {
+	case DLROrder:
+		stack := []binaryFrame[T]{{bn: bn}}
+		for len(stack) > 0 {
+			f := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !fn(f.bn, f.depth) {
+				return false
+			}
+
+			if f.bn.right != nil {
+				stack = append(stack, binaryFrame[T]{bn: f.bn.right, depth: f.depth + 1})
+			}
+			if f.bn.left != nil {
+				stack = append(stack, binaryFrame[T]{bn: f.bn.left, depth: f.depth + 1})
+			}
+		}
+	case LDROrder:
+		var stack []binaryFrame[T]
+		cur, depth := bn, 0
+		for cur != nil || len(stack) > 0 {
+			for ; cur != nil; cur, depth = cur.left, depth+1 {
+				stack = append(stack, binaryFrame[T]{bn: cur, depth: depth})
+			}
+
+			f := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !fn(f.bn, f.depth) {
+				return false
+			}
+			cur, depth = f.bn.right, f.depth+1
+		}
+	case LRDOrder:
+		stack := []binaryFrame[T]{{bn: bn}}
+		for len(stack) > 0 {
+			f := &stack[len(stack)-1]
+			if f.expanded {
+				stack = stack[:len(stack)-1]
+				if !fn(f.bn, f.depth) {
+					return false
+				}
+				continue
+			}
+
+			f.expanded = true
+			cur, depth := f.bn, f.depth+1
+			if cur.right != nil {
+				stack = append(stack, binaryFrame[T]{bn: cur.right, depth: depth})
+			}
+			if cur.left != nil {
+				stack = append(stack, binaryFrame[T]{bn: cur.left, depth: depth})
+			}
+		}
+	case LevelOrder:
+		queue := []binaryFrame[T]{{bn: bn}}
+		for i := 0; i < len(queue); i++ {
+			f := queue[i]
+			if !fn(f.bn, f.depth) {
+				return false
+			}
+
+			if f.bn.left != nil {
+				queue = append(queue, binaryFrame[T]{bn: f.bn.left, depth: f.depth + 1})
+			}
+			if f.bn.right != nil {
+				queue = append(queue, binaryFrame[T]{bn: f.bn.right, depth: f.depth + 1})
+			}
+		}
 	}
 
-	fn(bn.node.data)
+	return true
 }
 
-// AddNode 添加节点
+// AddNode 添加节点, 返回当前节点以便链式调用
+// 二叉搜索树按比较函数插入, 已存在相等的数据时替换; 普通二叉树按层序插入到第一个空位
 func (bn *BinaryNode[T]) AddNode(t T) *BinaryNode[T] {
-	//	TODO
+	if bn.node == nil {
+		bn.node = &Node[T]{data: t}
+		return bn
+	}
+
+	if bn.less != nil {
+		bn.insert(t)
+	} else {
+		bn.fill(t)
+	}
+
 	return bn
 }
+
+// fill 按层序插入到第一个空位
+func (bn *BinaryNode[T]) fill(t T) {
+	child := &BinaryNode[T]{node: &Node[T]{data: t}}
+	queue := []*BinaryNode[T]{bn}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		if cur.left == nil {
+			cur.left = child
+			return
+		}
+		if cur.right == nil {
+			cur.right = child
+			return
+		}
+		queue = append(queue, cur.left, cur.right)
+	}
+}
@@ -0,0 +1,142 @@
+package tree
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+/*
+	二叉树的序列化
+	嵌套形式: {"value":1,"left":{"value":2},"right":{"value":3}}
+	层序形式: [1,null,2,3], null表示空位, 空位的子节点不再列出, 末尾的null会被省略
+*/
+
+// binaryNodeJSON 二叉树节点的嵌套JSON结构
+type binaryNodeJSON[T any] struct {
+	Value T              `json:"value"`
+	Left  *BinaryNode[T] `json:"left,omitempty"`
+	Right *BinaryNode[T] `json:"right,omitempty"`
+}
+
+// MarshalJSON 实现json.Marshaler接口, 输出嵌套形式, 空树输出null
+func (bn *BinaryNode[T]) MarshalJSON() ([]byte, error) {
+	if bn.node == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(binaryNodeJSON[T]{
+		Value: bn.node.data,
+		Left:  bn.left,
+		Right: bn.right,
+	})
+}
+
+// UnmarshalJSON 实现json.Unmarshaler接口, 解析嵌套形式, null解析为空树
+// 二叉搜索树的比较函数会被保留, 但不会检查数据的顺序
+func (bn *BinaryNode[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*bn = BinaryNode[T]{less: bn.less}
+		return nil
+	}
+
+	var v binaryNodeJSON[T]
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*bn = BinaryNode[T]{
+		node:  &Node[T]{data: v.Value},
+		left:  v.Left,
+		right: v.Right,
+		less:  bn.less,
+	}
+	bn.setLess(bn.less)
+	return nil
+}
+
+// setLess 设置所有节点的比较函数
+func (bn *BinaryNode[T]) setLess(less func(a, b T) bool) {
+	bn.traverse(LevelOrder, func(n *BinaryNode[T], _ int) bool {
+		n.less = less
+		return true
+	})
+}
+
+// NewBinaryTreeLevelOrder 按带空位的层序形式新建二叉树, nil表示空位
+func NewBinaryTreeLevelOrder[T any](list ...*T) *BinaryNode[T] {
+	root := new(BinaryNode[T])
+	if len(list) == 0 || list[0] == nil {
+		return root
+	}
+
+	root.node = &Node[T]{data: *list[0]}
+	queue := []*BinaryNode[T]{root}
+	i := 1
+	for head := 0; head < len(queue) && i < len(list); head++ {
+		parent := queue[head]
+		for _, link := range []**BinaryNode[T]{&parent.left, &parent.right} {
+			if i >= len(list) {
+				break
+			}
+
+			if list[i] != nil {
+				*link = &BinaryNode[T]{node: &Node[T]{data: *list[i]}}
+				queue = append(queue, *link)
+			}
+			i++
+		}
+	}
+
+	return root
+}
+
+// LevelOrderList 获取带空位的层序形式, nil表示空位, 末尾的空位会被省略
+func (bn *BinaryNode[T]) LevelOrderList() []*T {
+	if bn.node == nil {
+		return nil
+	}
+
+	var list []*T
+	queue := []*BinaryNode[T]{bn}
+	for head := 0; head < len(queue); head++ {
+		n := queue[head]
+		if n == nil {
+			list = append(list, nil)
+			continue
+		}
+
+		data := n.node.data
+		list = append(list, &data)
+		queue = append(queue, n.left, n.right)
+	}
+
+	for len(list) > 0 && list[len(list)-1] == nil {
+		list = list[:len(list)-1]
+	}
+
+	return list
+}
+
+// MarshalLevelOrder 以带空位的层序形式输出JSON数组, 如[1,null,2,3]
+func (bn *BinaryNode[T]) MarshalLevelOrder() ([]byte, error) {
+	list := bn.LevelOrderList()
+	if list == nil {
+		list = []*T{}
+	}
+
+	return json.Marshal(list)
+}
+
+// UnmarshalLevelOrder 解析带空位的层序形式的JSON数组
+// 二叉搜索树的比较函数会被保留, 但不会检查数据的顺序
+func (bn *BinaryNode[T]) UnmarshalLevelOrder(data []byte) error {
+	var list []*T
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+
+	less := bn.less
+	*bn = *NewBinaryTreeLevelOrder(list...)
+	bn.setLess(less)
+	return nil
+}
@@ -0,0 +1,141 @@
+package tree
+
+/*
+	二叉树的结构查询, 均为非递归实现, 空树(根节点没有数据)视为没有节点
+*/
+
+// Height 获取树的高度, 即层数, 空树为0
+func (bn *BinaryNode[T]) Height() int {
+	height := 0
+	bn.traverse(LevelOrder, func(_ *BinaryNode[T], depth int) bool {
+		height = depth + 1
+		return true
+	})
+
+	return height
+}
+
+// Size 获取节点数量
+func (bn *BinaryNode[T]) Size() int {
+	size := 0
+	bn.traverse(LevelOrder, func(*BinaryNode[T], int) bool {
+		size++
+		return true
+	})
+
+	return size
+}
+
+// LeafCount 获取叶子节点数量
+func (bn *BinaryNode[T]) LeafCount() int {
+	count := 0
+	bn.traverse(LevelOrder, func(n *BinaryNode[T], _ int) bool {
+		if n.isLeaf() {
+			count++
+		}
+		return true
+	})
+
+	return count
+}
+
+func (bn *BinaryNode[T]) isLeaf() bool {
+	return bn.left == nil && bn.right == nil
+}
+
+// IsBalanced 是否为平衡二叉树, 即每个节点左右子树的高度差不超过1
+func (bn *BinaryNode[T]) IsBalanced() bool {
+	heights := make(map[*BinaryNode[T]]int)
+	return bn.traverse(LRDOrder, func(n *BinaryNode[T], _ int) bool {
+		left, right := heights[n.left], heights[n.right]
+		if left-right > 1 || right-left > 1 {
+			return false
+		}
+
+		if left < right {
+			left = right
+		}
+		heights[n] = left + 1
+		return true
+	})
+}
+
+// IsComplete 是否为完全二叉树, 即除最后一层外都是满的, 且最后一层的节点都靠左排列
+func (bn *BinaryNode[T]) IsComplete() bool {
+	// 层序遍历中出现空位后不能再有节点
+	gap := false
+	return bn.traverse(LevelOrder, func(n *BinaryNode[T], _ int) bool {
+		for _, child := range []*BinaryNode[T]{n.left, n.right} {
+			if child == nil {
+				gap = true
+			} else if gap {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// LCA 获取数据a和b的最近公共祖先, 节点也是自身的祖先
+// equal为nil时使用二叉搜索树的比较函数判断相等; 数据重复时取层序遍历中最先出现的节点; a或b不存在时返回false
+func (bn *BinaryNode[T]) LCA(a, b T, equal func(x, y T) bool) (result T, ok bool) {
+	if equal == nil {
+		if bn.less == nil {
+			return
+		}
+
+		equal = func(x, y T) bool {
+			return !bn.less(x, y) && !bn.less(y, x)
+		}
+	}
+
+	// 记录父节点, 直到同时找到a和b
+	parents := make(map[*BinaryNode[T]]*BinaryNode[T])
+	var nodeA, nodeB *BinaryNode[T]
+	bn.traverse(LevelOrder, func(n *BinaryNode[T], _ int) bool {
+		if nodeA == nil && equal(n.node.data, a) {
+			nodeA = n
+		}
+		if nodeB == nil && equal(n.node.data, b) {
+			nodeB = n
+		}
+		for _, child := range []*BinaryNode[T]{n.left, n.right} {
+			if child != nil {
+				parents[child] = n
+			}
+		}
+		return nodeA == nil || nodeB == nil
+	})
+	if nodeA == nil || nodeB == nil {
+		return
+	}
+
+	ancestors := make(map[*BinaryNode[T]]bool)
+	for n := nodeA; n != nil; n = parents[n] {
+		ancestors[n] = true
+	}
+	for n := nodeB; n != nil; n = parents[n] {
+		if ancestors[n] {
+			return n.node.data, true
+		}
+	}
+
+	return
+}
+
+// Paths 获取所有从根节点到叶子节点的路径, 按先序遍历的顺序排列
+func (bn *BinaryNode[T]) Paths() [][]T {
+	var (
+		paths [][]T
+		path  []T
+	)
+	bn.traverse(DLROrder, func(n *BinaryNode[T], depth int) bool {
+		path = append(path[:depth], n.node.data)
+		if n.isLeaf() {
+			paths = append(paths, append([]T(nil), path...))
+		}
+		return true
+	})
+
+	return paths
+}
@@ -1,6 +1,7 @@
 package tree
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -42,7 +43,270 @@ func TestBinary(t *testing.T) {
 
 	bt := NewBinaryTree[int](1)
 	bt.AddNode(2).AddNode(3).AddNode(4).AddNode(5).AddNode(6).AddNode(7).AddNode(8)
+	result := make([]int, 0, 8)
 	bt.DLR(func(i int) {
 		fmt.Printf("%d ", i)
+		result = append(result, i)
 	})
+	assert.Equal(t, []int{1, 2, 4, 8, 5, 3, 6, 7}, result)
+
+	bt = NewBinaryTree[int]()
+	bt.AddNode(1).AddNode(2).AddNode(3)
+	result = result[:0]
+	bt.LDR(func(i int) {
+		result = append(result, i)
+	})
+	assert.Equal(t, []int{2, 1, 3}, result)
+}
+
+func TestBSTree(t *testing.T) {
+	bst := NewBSTree[int](func(a, b int) bool { return a < b })
+	_, ok := bst.Min()
+	assert.False(t, ok)
+	assert.False(t, bst.Delete(1))
+
+	bst.AddNode(50).AddNode(30).AddNode(70).AddNode(20).AddNode(40).AddNode(60).AddNode(80).AddNode(35).AddNode(45)
+
+	inorder := func() []int {
+		var result []int
+		bst.LDR(func(i int) {
+			result = append(result, i)
+		})
+		return result
+	}
+	assert.Equal(t, []int{20, 30, 35, 40, 45, 50, 60, 70, 80}, inorder())
+
+	v, ok := bst.Search(45)
+	assert.True(t, ok)
+	assert.Equal(t, 45, v)
+	_, ok = bst.Search(46)
+	assert.False(t, ok)
+
+	v, _ = bst.Min()
+	assert.Equal(t, 20, v)
+	v, _ = bst.Max()
+	assert.Equal(t, 80, v)
+
+	floors := map[int][]int{10: nil, 20: {20}, 33: {30}, 47: {45}, 55: {50}, 99: {80}}
+	for k, want := range floors {
+		v, ok := bst.Floor(k)
+		assert.Equal(t, want != nil, ok, k)
+		if ok {
+			assert.Equal(t, want[0], v, k)
+		}
+	}
+	ceilings := map[int][]int{10: {20}, 33: {35}, 47: {50}, 80: {80}, 99: nil}
+	for k, want := range ceilings {
+		v, ok := bst.Ceiling(k)
+		assert.Equal(t, want != nil, ok, k)
+		if ok {
+			assert.Equal(t, want[0], v, k)
+		}
+	}
+
+	// 叶子节点、单子节点、双子节点(后继替换)、根节点
+	assert.True(t, bst.Delete(20))
+	assert.True(t, bst.Delete(30))
+	assert.True(t, bst.Delete(40))
+	assert.Equal(t, []int{35, 45, 50, 60, 70, 80}, inorder())
+	assert.True(t, bst.Delete(50))
+	assert.Equal(t, []int{35, 45, 60, 70, 80}, inorder())
+	assert.False(t, bst.Delete(50))
+
+	for _, i := range []int{35, 45, 60, 70} {
+		assert.True(t, bst.Delete(i))
+	}
+	assert.Equal(t, []int{80}, inorder())
+	assert.True(t, bst.Delete(80))
+	assert.Nil(t, inorder())
+	bst.AddNode(1)
+	assert.Equal(t, []int{1}, inorder())
+
+	// 相等的数据会被替换
+	type pair struct {
+		key   int
+		value string
+	}
+	kv := NewBSTree[pair](func(a, b pair) bool { return a.key < b.key })
+	kv.AddNode(pair{1, "a"}).AddNode(pair{2, "b"}).AddNode(pair{1, "c"})
+	p, ok := kv.Search(pair{key: 1})
+	assert.True(t, ok)
+	assert.Equal(t, "c", p.value)
+
+	// 普通二叉树不支持搜索
+	_, ok = NewBinaryTree[int](1, 2, 3).Search(1)
+	assert.False(t, ok)
+	assert.Panics(t, func() { NewBSTree[int](nil) })
+}
+
+func TestBinaryTraverse(t *testing.T) {
+	bt := NewBinaryTree[int](1, 2, 3, 4, 5, 6, 7, 8)
+
+	collect := func(order Order) []int {
+		var result []int
+		bt.Traverse(order, func(i, _ int) bool {
+			result = append(result, i)
+			return true
+		})
+		return result
+	}
+	assert.Equal(t, []int{1, 2, 4, 8, 5, 3, 6, 7}, collect(DLROrder))
+	assert.Equal(t, []int{8, 4, 2, 5, 1, 6, 3, 7}, collect(LDROrder))
+	assert.Equal(t, []int{8, 4, 5, 2, 6, 7, 3, 1}, collect(LRDOrder))
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, collect(LevelOrder))
+
+	// 提前终止
+	for _, order := range []Order{DLROrder, LDROrder, LRDOrder, LevelOrder} {
+		var result []int
+		assert.False(t, bt.Traverse(order, func(i, _ int) bool {
+			result = append(result, i)
+			return len(result) < 3
+		}))
+		assert.Equal(t, collect(order)[:3], result)
+	}
+
+	var result []int
+	bt.BFS(func(i int) bool {
+		result = append(result, i)
+		return i != 4
+	})
+	assert.Equal(t, []int{1, 2, 3, 4}, result)
+
+	depths := map[int]int{}
+	bt.Traverse(LDROrder, func(i, depth int) bool {
+		depths[i] = depth
+		return true
+	})
+	assert.Equal(t, map[int]int{1: 0, 2: 1, 3: 1, 4: 2, 5: 2, 6: 2, 7: 2, 8: 3}, depths)
+
+	assert.Equal(t, [][]int{{1}, {2, 3}, {4, 5, 6, 7}, {8}}, bt.Levels())
+	assert.Nil(t, NewBinaryTree[int]().Levels())
+	assert.True(t, NewBinaryTree[int]().Traverse(DLROrder, func(int, int) bool { return false }))
+
+	// 退化为链表的深层树不会栈溢出
+	deep := NewBinaryTree[int](0)
+	cur := deep
+	for i := 1; i < 1000000; i++ {
+		cur.right = &BinaryNode[int]{node: &Node[int]{data: i}}
+		cur = cur.right
+	}
+	for _, order := range []Order{DLROrder, LDROrder, LRDOrder, LevelOrder} {
+		count := 0
+		deep.Traverse(order, func(int, int) bool {
+			count++
+			return true
+		})
+		assert.Equal(t, 1000000, count)
+	}
+}
+
+func TestBinaryQuery(t *testing.T) {
+	empty := NewBinaryTree[int]()
+	assert.Zero(t, empty.Height())
+	assert.Zero(t, empty.Size())
+	assert.Zero(t, empty.LeafCount())
+	assert.True(t, empty.IsBalanced())
+	assert.True(t, empty.IsComplete())
+	assert.Nil(t, empty.Paths())
+
+	bt := NewBinaryTree[int](1, 2, 3, 4, 5, 6)
+	assert.Equal(t, 3, bt.Height())
+	assert.Equal(t, 6, bt.Size())
+	assert.Equal(t, 3, bt.LeafCount())
+	assert.True(t, bt.IsBalanced())
+	assert.True(t, bt.IsComplete())
+	assert.Equal(t, [][]int{{1, 2, 4}, {1, 2, 5}, {1, 3, 6}}, bt.Paths())
+
+	equal := func(x, y int) bool { return x == y }
+	lcas := []struct{ a, b, lca int }{
+		{4, 5, 2}, {4, 6, 1}, {2, 4, 2}, {6, 6, 6}, {3, 6, 3},
+	}
+	for _, test := range lcas {
+		v, ok := bt.LCA(test.a, test.b, equal)
+		assert.True(t, ok)
+		assert.Equal(t, test.lca, v, test)
+	}
+	_, ok := bt.LCA(4, 9, equal)
+	assert.False(t, ok)
+	_, ok = bt.LCA(4, 5, nil)
+	assert.False(t, ok)
+
+	// 最后一层不靠左
+	bt.left.left = nil
+	assert.False(t, bt.IsComplete())
+	assert.True(t, bt.IsBalanced())
+
+	// 左子树比右子树高2
+	chain := NewBinaryTree[int](1, 2)
+	chain.left.left = &BinaryNode[int]{node: &Node[int]{data: 3}}
+	assert.False(t, chain.IsBalanced())
+	assert.False(t, chain.IsComplete())
+	assert.Equal(t, 3, chain.Height())
+	assert.Equal(t, 1, chain.LeafCount())
+	assert.Equal(t, [][]int{{1, 2, 3}}, chain.Paths())
+
+	bst := NewBSTree[int](func(a, b int) bool { return a < b })
+	bst.AddNode(50).AddNode(30).AddNode(70).AddNode(20).AddNode(40).AddNode(80)
+	v, ok := bst.LCA(20, 40, nil)
+	assert.True(t, ok)
+	assert.Equal(t, 30, v)
+	v, _ = bst.LCA(20, 80, nil)
+	assert.Equal(t, 50, v)
+}
+
+func TestBinaryJSON(t *testing.T) {
+	bt := NewBinaryTree[int](1, 2, 3, 4)
+	b, err := json.Marshal(bt)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"value":1,"left":{"value":2,"left":{"value":4}},"right":{"value":3}}`, string(b))
+
+	decoded := new(BinaryNode[int])
+	assert.NoError(t, json.Unmarshal(b, decoded))
+	assert.Equal(t, [][]int{{1}, {2, 3}, {4}}, decoded.Levels())
+
+	b, err = json.Marshal(NewBinaryTree[int]())
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(b))
+	assert.NoError(t, json.Unmarshal([]byte("null"), decoded))
+	assert.Zero(t, decoded.Size())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"value":"a"}`), decoded))
+
+	// 比较函数会被保留
+	bst := NewBSTree[int](func(a, b int) bool { return a < b })
+	assert.NoError(t, json.Unmarshal([]byte(`{"value":5,"left":{"value":3}}`), bst))
+	bst.AddNode(8).AddNode(4)
+	result := make([]int, 0, 4)
+	bst.LDR(func(i int) {
+		result = append(result, i)
+	})
+	assert.Equal(t, []int{3, 4, 5, 8}, result)
+}
+
+func TestBinaryLevelOrder(t *testing.T) {
+	tests := []string{
+		`[1,null,2,3]`,
+		`[1,2,3,4,5,6,7]`,
+		`[5,4,8,11,null,13,4,7,2,null,null,null,1]`,
+		`[1,null,2,null,3]`,
+		`[]`,
+	}
+	for _, test := range tests {
+		bt := new(BinaryNode[int])
+		assert.NoError(t, bt.UnmarshalLevelOrder([]byte(test)), test)
+
+		b, err := bt.MarshalLevelOrder()
+		assert.NoError(t, err)
+		assert.Equal(t, test, string(b))
+	}
+
+	one, two, three := 1, 2, 3
+	bt := NewBinaryTreeLevelOrder(&one, nil, &two, &three)
+	assert.Equal(t, [][]int{{1}, {2}, {3}}, bt.Levels())
+	assert.Equal(t, [][]int{{1, 2, 3}}, bt.Paths())
+	assert.Equal(t, []*int{&one, nil, &two, &three}, bt.LevelOrderList())
+
+	assert.Zero(t, NewBinaryTreeLevelOrder[int](nil, &one).Size())
+	assert.Nil(t, NewBinaryTree[int]().LevelOrderList())
+	assert.Error(t, new(BinaryNode[int]).UnmarshalLevelOrder([]byte(`{}`)))
 }
@@ -0,0 +1,202 @@
+package tree
+
+/*
+	二叉搜索树, 左子树的数据均小于当前节点, 右子树的数据均大于当前节点
+	ex:
+	bst := NewBSTree[int](func(a, b int) bool { return a < b })
+	bst.AddNode(5).AddNode(3).AddNode(8)
+
+	以下方法只对二叉搜索树有效, 普通二叉树上调用时视为空树
+*/
+
+// NewBSTree 新建二叉搜索树, less为数据的比较函数
+func NewBSTree[T any](less func(a, b T) bool) *BinaryNode[T] {
+	if less == nil {
+		panic("less must not be nil")
+	}
+
+	return &BinaryNode[T]{
+		less: less,
+	}
+}
+
+// insert 按比较函数插入, 已存在相等的数据时替换
+func (bn *BinaryNode[T]) insert(t T) {
+	cur := bn
+	for {
+		switch {
+		case bn.less(t, cur.node.data):
+			if cur.left == nil {
+				cur.left = bn.newChild(t)
+				return
+			}
+			cur = cur.left
+		case bn.less(cur.node.data, t):
+			if cur.right == nil {
+				cur.right = bn.newChild(t)
+				return
+			}
+			cur = cur.right
+		default:
+			cur.node.data = t
+			return
+		}
+	}
+}
+
+func (bn *BinaryNode[T]) newChild(t T) *BinaryNode[T] {
+	return &BinaryNode[T]{
+		node: &Node[T]{data: t},
+		less: bn.less,
+	}
+}
+
+// isBST 是否为非空的二叉搜索树
+func (bn *BinaryNode[T]) isBST() bool {
+	return bn.less != nil && bn.node != nil
+}
+
+// Search 查找与t相等的数据
+func (bn *BinaryNode[T]) Search(t T) (result T, ok bool) {
+	if !bn.isBST() {
+		return
+	}
+
+	for cur := bn; cur != nil; {
+		switch {
+		case bn.less(t, cur.node.data):
+			cur = cur.left
+		case bn.less(cur.node.data, t):
+			cur = cur.right
+		default:
+			return cur.node.data, true
+		}
+	}
+
+	return
+}
+
+// Delete 删除与t相等的数据, 有两个子节点时用后继节点替换, 数据不存在时返回false
+func (bn *BinaryNode[T]) Delete(t T) bool {
+	if !bn.isBST() {
+		return false
+	}
+
+	// link 指向当前节点的父节点中的指针, 根节点为nil
+	var link **BinaryNode[T]
+	cur := bn
+	for {
+		if cur == nil {
+			return false
+		}
+
+		if bn.less(t, cur.node.data) {
+			link, cur = &cur.left, cur.left
+		} else if bn.less(cur.node.data, t) {
+			link, cur = &cur.right, cur.right
+		} else {
+			break
+		}
+	}
+
+	if cur.left != nil && cur.right != nil {
+		// 用右子树的最小节点(后继)替换当前节点的数据, 再删除后继节点
+		successorLink, successor := &cur.right, cur.right
+		for successor.left != nil {
+			successorLink, successor = &successor.left, successor.left
+		}
+
+		cur.node = successor.node
+		*successorLink = successor.right
+		return true
+	}
+
+	child := cur.left
+	if child == nil {
+		child = cur.right
+	}
+
+	if link != nil {
+		*link = child
+		return true
+	}
+
+	// 删除根节点时保持根节点的指针不变
+	if child == nil {
+		bn.node = nil
+	} else {
+		*bn = *child
+	}
+
+	return true
+}
+
+// Min 获取最小的数据
+func (bn *BinaryNode[T]) Min() (result T, ok bool) {
+	if !bn.isBST() {
+		return
+	}
+
+	cur := bn
+	for cur.left != nil {
+		cur = cur.left
+	}
+
+	return cur.node.data, true
+}
+
+// Max 获取最大的数据
+func (bn *BinaryNode[T]) Max() (result T, ok bool) {
+	if !bn.isBST() {
+		return
+	}
+
+	cur := bn
+	for cur.right != nil {
+		cur = cur.right
+	}
+
+	return cur.node.data, true
+}
+
+// Floor 获取小于等于t的最大数据
+func (bn *BinaryNode[T]) Floor(t T) (result T, ok bool) {
+	if !bn.isBST() {
+		return
+	}
+
+	for cur := bn; cur != nil; {
+		switch {
+		case bn.less(t, cur.node.data):
+			cur = cur.left
+		case bn.less(cur.node.data, t):
+			result, ok = cur.node.data, true
+			cur = cur.right
+		default:
+			return cur.node.data, true
+		}
+	}
+
+	return
+}
+
+// Ceiling 获取大于等于t的最小数据
+func (bn *BinaryNode[T]) Ceiling(t T) (result T, ok bool) {
+	if !bn.isBST() {
+		return
+	}
+
+	for cur := bn; cur != nil; {
+		switch {
+		case bn.less(cur.node.data, t):
+			cur = cur.right
+		case bn.less(t, cur.node.data):
+			result, ok = cur.node.data, true
+			cur = cur.left
+		default:
+			return cur.node.data, true
+		}
+	}
+
+	return
+}
@@ -0,0 +1,55 @@
+package tree
+
+import "errors"
+
+var (
+	// ErrDuplicatePath 路径已注册过数据
+	ErrDuplicatePath = errors.New("data are already registered for path")
+	// ErrInvalidWildcard 通配符写法不合法
+	ErrInvalidWildcard = errors.New("invalid wildcard")
+	// ErrInvalidConfig 路径树配置不合法
+	ErrInvalidConfig = errors.New("invalid path tree config")
+	// ErrUnknownRoute 命名路由不存在
+	ErrUnknownRoute = errors.New("unknown route name")
+	// ErrInvalidURLParams 生成URL的参数不合法
+	ErrInvalidURLParams = errors.New("invalid url params")
+	// ErrCycle 节点关系存在环
+	ErrCycle = errors.New("cycle in tree")
+	// ErrOrphan 父节点不存在
+	ErrOrphan = errors.New("orphan node")
+	// ErrDuplicateID 节点标识重复
+	ErrDuplicateID = errors.New("duplicate node id")
+	// ErrInvalidInterval 区间起点大于终点
+	ErrInvalidInterval = errors.New("invalid interval")
+)
+
+// ConflictError 新路径与已存在的路径冲突
+type ConflictError struct {
+	// Segment 新路径中发生冲突的路径段
+	Segment string
+	// Path 新路径
+	Path string
+	// Existing 已存在的通配符(Wildcard为true时)或路径段
+	Existing string
+	// Prefix 已存在的冲突前缀
+	Prefix string
+	// Wildcard 是否与已存在的通配符冲突, 否则为全匹配通配符与已存在的路径段冲突
+	Wildcard bool
+}
+
+// Error 实现error接口
+func (e *ConflictError) Error() string {
+	if e.Wildcard {
+		return "'" + e.Segment +
+			"' in new path '" + e.Path +
+			"' conflicts with existing wildcard '" + e.Existing +
+			"' in existing prefix '" + e.Prefix +
+			"'"
+	}
+
+	return "catch-all wildcard '" + e.Segment +
+		"' in new path '" + e.Path +
+		"' conflicts with existing path segment '" + e.Existing +
+		"' in existing prefix '" + e.Prefix +
+		"'"
+}
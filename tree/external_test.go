@@ -0,0 +1,52 @@
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/liuxh-go/chopper/tree"
+	"github.com/stretchr/testify/assert"
+)
+
+// 包外只能使用导出的API, 查找时需要回退的路径不能panic
+
+func TestAtomicPathNodeExternal(t *testing.T) {
+	apn, err := tree.NewAtomicPathNode[string]()
+	assert.NoError(t, err)
+
+	routes := []string{"/users/:id", "/users/new"}
+	for i := range routes {
+		apn.AddNode(routes[i], &routes[i])
+	}
+
+	params := make(tree.Params, 0, 1)
+	v := apn.GetValue("/users/newx", &params)
+	if assert.NotNil(t, v.Data) {
+		assert.Equal(t, "/users/:id", *v.Data)
+		assert.Equal(t, "newx", params.ByName("id"))
+	}
+	v = apn.GetValue("/users/new", nil)
+	if assert.NotNil(t, v.Data) {
+		assert.Equal(t, "/users/new", *v.Data)
+	}
+	assert.Nil(t, apn.GetValue("/users", nil).Data)
+}
+func TestHostPathExternal(t *testing.T) {
+	hn := &tree.HostNode[tree.PathNode[string]]{}
+	pn := &tree.PathNode[string]{}
+	hn.AddNode(":tenant.example.com", pn)
+
+	routes := []string{"/users/:id", "/users/new"}
+	for i := range routes {
+		pn.AddNode(routes[i], &routes[i])
+	}
+
+	hostParams, pathParams := make(tree.Params, 0, 1), make(tree.Params, 0, 1)
+	hv := hn.GetValue("acme.example.com", &hostParams)
+	if assert.NotNil(t, hv.Data) {
+		v := hv.Data.GetValue("/users/newx", &pathParams, nil)
+		if assert.NotNil(t, v.Data) {
+			assert.Equal(t, "acme", hostParams.ByName("tenant"))
+			assert.Equal(t, "newx", pathParams.ByName("id"))
+		}
+	}
+}
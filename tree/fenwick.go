@@ -0,0 +1,98 @@
+package tree
+
+import "golang.org/x/exp/constraints"
+
+/*
+	树状数组, 维护数组的前缀和, 单点修改和前缀和查询均为O(log n), 区间均为左闭右开[lo, hi)
+	ex:
+	ft := NewFenwickTree([]int{1, 2, 3})
+	ft.Add(0, 10)
+	ft.PrefixSum(2)   // 13
+	ft.RangeSum(1, 3) // 5
+*/
+
+// Number 树状数组支持的数值类型
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
+// FenwickTree 树状数组, 非并发安全
+type FenwickTree[T Number] struct {
+	// tree 下标从1开始, tree[i]为(i-lowbit(i), i]内数据的和
+	tree []T
+}
+
+// NewFenwickTree 构造函数, 以list为初始数据在O(n)内构建
+func NewFenwickTree[T Number](list []T) *FenwickTree[T] {
+	tree := make([]T, len(list)+1)
+	copy(tree[1:], list)
+	for i := 1; i < len(tree); i++ {
+		if j := i + i&-i; j < len(tree) {
+			tree[j] += tree[i]
+		}
+	}
+
+	return &FenwickTree[T]{
+		tree: tree,
+	}
+}
+
+// Len 获取数组长度
+func (ft *FenwickTree[T]) Len() int {
+	return len(ft.tree) - 1
+}
+
+// Add 将下标为i的数据加上delta, 下标越界时返回false
+func (ft *FenwickTree[T]) Add(i int, delta T) bool {
+	if i < 0 || i >= ft.Len() {
+		return false
+	}
+
+	for i++; i < len(ft.tree); i += i & -i {
+		ft.tree[i] += delta
+	}
+
+	return true
+}
+
+// Set 设置下标为i的数据, 下标越界时返回false
+func (ft *FenwickTree[T]) Set(i int, t T) bool {
+	old, ok := ft.Get(i)
+	if !ok {
+		return false
+	}
+
+	return ft.Add(i, t-old)
+}
+
+// Get 获取下标为i的数据, 下标越界时返回false
+func (ft *FenwickTree[T]) Get(i int) (t T, ok bool) {
+	if i < 0 || i >= ft.Len() {
+		return
+	}
+
+	return ft.RangeSum(i, i+1), true
+}
+
+// PrefixSum 获取前n个数据的和, n超出范围时按边界计算
+func (ft *FenwickTree[T]) PrefixSum(n int) T {
+	if n > ft.Len() {
+		n = ft.Len()
+	}
+
+	var sum T
+	for ; n > 0; n -= n & -n {
+		sum += ft.tree[n]
+	}
+
+	return sum
+}
+
+// RangeSum 获取[lo, hi)内数据的和, 区间为空时返回0
+func (ft *FenwickTree[T]) RangeSum(lo, hi int) T {
+	if lo >= hi {
+		return 0
+	}
+
+	return ft.PrefixSum(hi) - ft.PrefixSum(lo)
+}
@@ -0,0 +1,54 @@
+package tree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFenwickTree(t *testing.T) {
+	ft := NewFenwickTree([]int{1, 2, 3, 4, 5})
+	assert.Equal(t, 5, ft.Len())
+	assert.Equal(t, 6, ft.PrefixSum(3))
+	assert.Equal(t, 15, ft.PrefixSum(100))
+	assert.Equal(t, 0, ft.PrefixSum(0))
+	assert.Equal(t, 9, ft.RangeSum(1, 4))
+	assert.Equal(t, 0, ft.RangeSum(3, 1))
+
+	assert.True(t, ft.Add(0, 10))
+	assert.Equal(t, 13, ft.PrefixSum(2))
+	assert.True(t, ft.Set(4, -5))
+	assert.Equal(t, 15, ft.PrefixSum(5))
+	v, ok := ft.Get(4)
+	assert.True(t, ok)
+	assert.Equal(t, -5, v)
+
+	assert.False(t, ft.Add(5, 1))
+	assert.False(t, ft.Set(-1, 1))
+	_, ok = ft.Get(5)
+	assert.False(t, ok)
+
+	floats := NewFenwickTree(make([]float64, 3))
+	floats.Add(1, 0.5)
+	assert.Equal(t, 0.5, floats.RangeSum(1, 3))
+
+	r := rand.New(rand.NewSource(1))
+	list := make([]int, 200)
+	ft = NewFenwickTree[int](nil)
+	assert.Equal(t, 0, ft.PrefixSum(1))
+	ft = NewFenwickTree(list)
+	for i := 0; i < 1000; i++ {
+		j, delta := r.Intn(len(list)), r.Intn(100)-50
+		ft.Add(j, delta)
+		list[j] += delta
+
+		lo := r.Intn(len(list))
+		hi := lo + r.Intn(len(list)-lo+1)
+		want := 0
+		for _, v := range list[lo:hi] {
+			want += v
+		}
+		assert.Equal(t, want, ft.RangeSum(lo, hi))
+	}
+}
@@ -0,0 +1,250 @@
+package tree
+
+/*
+	二叉堆, 以切片存储完全二叉树, 下标i的子节点为2i+1和2i+2
+	堆顶为比较函数意义下最小的数据, 传入 a > b 的比较函数即为大顶堆
+	ex:
+	h := NewHeap[int](func(a, b int) bool { return a < b }, 3, 1, 2)
+	h.Push(0)
+	h.Pop() // 0
+*/
+
+// Heap 二叉堆, 非并发安全
+type Heap[T any] struct {
+	list []T
+	less func(a, b T) bool
+	// moved 数据移动到新下标时调用, 用于维护外部索引
+	moved func(t T, i int)
+}
+
+// NewHeap 构造函数, 以O(n)的时间复杂度将list整理为堆, list会被复制
+func NewHeap[T any](less func(a, b T) bool, list ...T) *Heap[T] {
+	if less == nil {
+		panic("less must not be nil")
+	}
+
+	h := &Heap[T]{
+		list: append([]T(nil), list...),
+		less: less,
+	}
+	h.init()
+
+	return h
+}
+
+// init 自底向上整理为堆
+func (h *Heap[T]) init() {
+	for i := len(h.list)/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+}
+
+// Len 获取数据数量
+func (h *Heap[T]) Len() int {
+	return len(h.list)
+}
+
+// Push 添加数据
+func (h *Heap[T]) Push(t T) {
+	h.list = append(h.list, t)
+	h.setMoved(len(h.list) - 1)
+	h.up(len(h.list) - 1)
+}
+
+// Peek 获取堆顶数据
+func (h *Heap[T]) Peek() (t T, ok bool) {
+	if len(h.list) == 0 {
+		return
+	}
+
+	return h.list[0], true
+}
+
+// Pop 弹出堆顶数据
+func (h *Heap[T]) Pop() (t T, ok bool) {
+	return h.Remove(0)
+}
+
+// Remove 删除下标为i的数据, 下标越界时返回false
+func (h *Heap[T]) Remove(i int) (t T, ok bool) {
+	n := len(h.list) - 1
+	if i < 0 || i > n {
+		return
+	}
+
+	if i != n {
+		h.swap(i, n)
+	}
+	t = h.list[n]
+
+	var zero T
+	h.list[n] = zero
+	h.list = h.list[:n]
+
+	if i != n {
+		h.Fix(i)
+	}
+
+	return t, true
+}
+
+// Fix 下标为i的数据改变后重新调整位置, 下标越界时不做处理
+func (h *Heap[T]) Fix(i int) {
+	if i < 0 || i >= len(h.list) {
+		return
+	}
+
+	if !h.down(i) {
+		h.up(i)
+	}
+}
+
+func (h *Heap[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.list[i], h.list[parent]) {
+			break
+		}
+
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+// down 向下调整, 返回是否发生了移动
+func (h *Heap[T]) down(i int) bool {
+	start := i
+	for {
+		child := 2*i + 1
+		if child >= len(h.list) {
+			break
+		}
+
+		if right := child + 1; right < len(h.list) && h.less(h.list[right], h.list[child]) {
+			child = right
+		}
+		if !h.less(h.list[child], h.list[i]) {
+			break
+		}
+
+		h.swap(i, child)
+		i = child
+	}
+
+	return i > start
+}
+
+func (h *Heap[T]) swap(i, j int) {
+	h.list[i], h.list[j] = h.list[j], h.list[i]
+	h.setMoved(i)
+	h.setMoved(j)
+}
+
+func (h *Heap[T]) setMoved(i int) {
+	if h.moved != nil {
+		h.moved(h.list[i], i)
+	}
+}
+
+// pqItem 优先队列中的元素
+type pqItem[K comparable, P any] struct {
+	key      K
+	priority P
+	index    int
+}
+
+// PriorityQueue 带索引的优先队列, 可以按键修改优先级或删除, 非并发安全
+type PriorityQueue[K comparable, P any] struct {
+	heap  *Heap[*pqItem[K, P]]
+	items map[K]*pqItem[K, P]
+}
+
+// NewPriorityQueue 构造函数, less为优先级的比较函数, 优先级最小的元素先出队
+func NewPriorityQueue[K comparable, P any](less func(a, b P) bool) *PriorityQueue[K, P] {
+	if less == nil {
+		panic("less must not be nil")
+	}
+
+	h := NewHeap(func(a, b *pqItem[K, P]) bool {
+		return less(a.priority, b.priority)
+	})
+	h.moved = func(item *pqItem[K, P], i int) {
+		item.index = i
+	}
+
+	return &PriorityQueue[K, P]{
+		heap:  h,
+		items: make(map[K]*pqItem[K, P]),
+	}
+}
+
+// Len 获取元素数量
+func (pq *PriorityQueue[K, P]) Len() int {
+	return pq.heap.Len()
+}
+
+// Push 添加元素, 键已存在时更新优先级
+func (pq *PriorityQueue[K, P]) Push(key K, priority P) {
+	if pq.Update(key, priority) {
+		return
+	}
+
+	item := &pqItem[K, P]{key: key, priority: priority}
+	pq.items[key] = item
+	pq.heap.Push(item)
+}
+
+// Update 更新键的优先级, 键不存在时返回false
+func (pq *PriorityQueue[K, P]) Update(key K, priority P) bool {
+	item, exists := pq.items[key]
+	if !exists {
+		return false
+	}
+
+	item.priority = priority
+	pq.heap.Fix(item.index)
+	return true
+}
+
+// Priority 获取键的优先级
+func (pq *PriorityQueue[K, P]) Priority(key K) (priority P, ok bool) {
+	item, exists := pq.items[key]
+	if !exists {
+		return
+	}
+
+	return item.priority, true
+}
+
+// Peek 获取优先级最小的元素
+func (pq *PriorityQueue[K, P]) Peek() (key K, priority P, ok bool) {
+	item, exists := pq.heap.Peek()
+	if !exists {
+		return
+	}
+
+	return item.key, item.priority, true
+}
+
+// Pop 弹出优先级最小的元素
+func (pq *PriorityQueue[K, P]) Pop() (key K, priority P, ok bool) {
+	item, exists := pq.heap.Pop()
+	if !exists {
+		return
+	}
+
+	delete(pq.items, item.key)
+	return item.key, item.priority, true
+}
+
+// Remove 删除键对应的元素, 键不存在时返回false
+func (pq *PriorityQueue[K, P]) Remove(key K) bool {
+	item, exists := pq.items[key]
+	if !exists {
+		return false
+	}
+
+	pq.heap.Remove(item.index)
+	delete(pq.items, key)
+	return true
+}
@@ -0,0 +1,141 @@
+package tree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// checkHeap 检查堆的性质
+func checkHeap[T any](t *testing.T, h *Heap[T]) {
+	for i := 1; i < len(h.list); i++ {
+		assert.False(t, h.less(h.list[i], h.list[(i-1)/2]), i)
+	}
+}
+
+func TestHeap(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	h := NewHeap(less)
+	_, ok := h.Pop()
+	assert.False(t, ok)
+	_, ok = h.Peek()
+	assert.False(t, ok)
+	_, ok = h.Remove(0)
+	assert.False(t, ok)
+
+	r := rand.New(rand.NewSource(1))
+	list := r.Perm(1000)
+	h = NewHeap(less, list...)
+	checkHeap(t, h)
+	assert.Equal(t, 1000, h.Len())
+	assert.NotEqual(t, list, h.list, "list is copied")
+
+	for i := 0; i < 100; i++ {
+		h.Push(r.Intn(2000))
+	}
+	checkHeap(t, h)
+
+	// 修改任意下标的数据后调整
+	for i := 0; i < 100; i++ {
+		j := r.Intn(h.Len())
+		h.list[j] = r.Intn(2000)
+		h.Fix(j)
+		checkHeap(t, h)
+	}
+
+	for i := 0; i < 100; i++ {
+		_, ok := h.Remove(r.Intn(h.Len()))
+		assert.True(t, ok)
+		checkHeap(t, h)
+	}
+	_, ok = h.Remove(h.Len())
+	assert.False(t, ok)
+
+	expected := append([]int(nil), h.list...)
+	sort.Ints(expected)
+	top, _ := h.Peek()
+	assert.Equal(t, expected[0], top)
+
+	result := make([]int, 0, len(expected))
+	for h.Len() > 0 {
+		v, ok := h.Pop()
+		assert.True(t, ok)
+		result = append(result, v)
+	}
+	assert.Equal(t, expected, result)
+
+	// 大顶堆
+	maxHeap := NewHeap(func(a, b int) bool { return a > b }, 1, 5, 3)
+	v, _ := maxHeap.Pop()
+	assert.Equal(t, 5, v)
+
+	assert.Panics(t, func() { NewHeap[int](nil) })
+}
+
+func TestPriorityQueue(t *testing.T) {
+	pq := NewPriorityQueue[string, int](func(a, b int) bool { return a < b })
+	_, _, ok := pq.Pop()
+	assert.False(t, ok)
+
+	pq.Push("a", 5)
+	pq.Push("b", 3)
+	pq.Push("c", 8)
+	pq.Push("d", 1)
+	assert.Equal(t, 4, pq.Len())
+
+	key, priority, ok := pq.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, "d", key)
+	assert.Equal(t, 1, priority)
+
+	assert.True(t, pq.Update("c", 0))
+	assert.False(t, pq.Update("e", 0))
+	pq.Push("d", 10)
+	assert.Equal(t, 4, pq.Len())
+	p, ok := pq.Priority("d")
+	assert.True(t, ok)
+	assert.Equal(t, 10, p)
+	_, ok = pq.Priority("e")
+	assert.False(t, ok)
+
+	assert.True(t, pq.Remove("b"))
+	assert.False(t, pq.Remove("b"))
+
+	var keys []string
+	for pq.Len() > 0 {
+		key, _, ok := pq.Pop()
+		assert.True(t, ok)
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []string{"c", "a", "d"}, keys)
+	assert.Empty(t, pq.items)
+
+	// 随机更新后仍按优先级出队
+	r := rand.New(rand.NewSource(1))
+	ipq := NewPriorityQueue[int, int](func(a, b int) bool { return a < b })
+	priorities := map[int]int{}
+	for i := 0; i < 500; i++ {
+		k, p := r.Intn(100), r.Intn(1000)
+		ipq.Push(k, p)
+		priorities[k] = p
+		if r.Intn(5) == 0 {
+			ipq.Remove(k)
+			delete(priorities, k)
+		}
+		checkHeap(t, ipq.heap)
+	}
+	assert.Equal(t, len(priorities), ipq.Len())
+
+	last := -1
+	for ipq.Len() > 0 {
+		k, p, _ := ipq.Pop()
+		assert.Equal(t, priorities[k], p)
+		assert.GreaterOrEqual(t, p, last)
+		last = p
+	}
+
+	assert.Panics(t, func() { NewPriorityQueue[int, int](nil) })
+}
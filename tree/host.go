@@ -0,0 +1,259 @@
+package tree
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+	主机名树, 从右向左逐个标签(label)匹配主机名
+	ex:
+	example.com
+	:tenant.example.com
+	*.cdn.example.com
+	*sub.example.com
+
+	支持的通配符(均需占据整个标签):
+	*      匹配任意单个标签, 不记录参数
+	:name  命名参数, 匹配任意单个标签
+	*name  全匹配, 匹配剩余的一个或多个标签, 只能位于最左侧
+
+	同一位置的匹配优先级: 静态标签 > 单标签通配符 > 全匹配, 匹配失败时回退尝试下一优先级
+	主机名不区分大小写, 末尾的'.'会被忽略, 端口需由调用方去除
+	与 PathNode 组合使用时可以将路径树作为数据, 如 HostNode[PathNode[T]], 查找路径时skippedNodes可传nil
+*/
+
+const hostSeparator = "."
+
+// HostNode 主机名树节点, 零值即可使用
+type HostNode[T any] struct {
+	label    string
+	nType    nodeType
+	children []*HostNode[T]
+	// wildChild 单标签通配符子节点
+	wildChild *HostNode[T]
+	// catchAllChild 全匹配子节点
+	catchAllChild *HostNode[T]
+	pattern       string
+	data          *T
+}
+
+// AddNode 添加主机名, 不合法或与已有主机名冲突时panic
+func (hn *HostNode[T]) AddNode(pattern string, t *T) {
+	if err := hn.TryAddNode(pattern, t); err != nil {
+		panic(err)
+	}
+}
+
+// TryAddNode 添加主机名, 不合法或与已有主机名冲突时返回错误, 树不会被修改
+// 错误可通过 errors.Is 判断 ErrDuplicatePath、ErrInvalidWildcard, 或通过 errors.As 获取 *ConflictError
+func (hn *HostNode[T]) TryAddNode(pattern string, t *T) error {
+	labels, err := splitHostPattern(pattern)
+	if err != nil {
+		return err
+	}
+
+	// 先检查冲突, 确保失败时不会留下多余的节点
+	n := hn
+	for i := len(labels) - 1; i >= 0 && n != nil; i-- {
+		label := labels[i]
+		var existing *HostNode[T]
+		switch label[0] {
+		case ':', '*':
+			existing = n.wildChild
+			if label[0] == '*' && len(label) > 1 {
+				existing = n.catchAllChild
+			}
+
+			if existing != nil && existing.label != label {
+				return &ConflictError{
+					Segment:  label,
+					Path:     pattern,
+					Existing: existing.label,
+					Prefix:   strings.Join(append([]string{existing.label}, labels[i+1:]...), hostSeparator),
+					Wildcard: true,
+				}
+			}
+		default:
+			existing = n.staticChild(label)
+		}
+		n = existing
+	}
+
+	if n != nil && n.data != nil {
+		return fmt.Errorf("%w '%s'", ErrDuplicatePath, pattern)
+	}
+
+	n = hn
+	n.nType = root
+	for i := len(labels) - 1; i >= 0; i-- {
+		n = n.addChild(labels[i])
+	}
+	n.pattern = pattern
+	n.data = t
+
+	return nil
+}
+
+// splitHostPattern 拆分并检查主机名模式, 静态标签统一转为小写
+func splitHostPattern(pattern string) ([]string, error) {
+	host := strings.TrimSuffix(pattern, hostSeparator)
+	if host == "" {
+		return nil, fmt.Errorf("%w: empty host pattern", ErrInvalidWildcard)
+	}
+
+	labels := strings.Split(host, hostSeparator)
+	for i, label := range labels {
+		switch {
+		case label == "":
+			return nil, fmt.Errorf("%w: empty label in host pattern '%s'", ErrInvalidWildcard, pattern)
+		case label[0] == ':':
+			if len(label) < 2 {
+				return nil, fmt.Errorf("%w: wildcards must be named with a non-empty name in host pattern '%s'",
+					ErrInvalidWildcard, pattern)
+			}
+		case label[0] == '*':
+			if len(label) > 1 && i != 0 {
+				return nil, fmt.Errorf("%w: catch-all labels are only allowed at the left of host pattern '%s'",
+					ErrInvalidWildcard, pattern)
+			}
+		default:
+			labels[i] = strings.ToLower(label)
+		}
+
+		if strings.ContainsAny(label[1:], ":*") {
+			return nil, fmt.Errorf("%w: only one wildcard per label is allowed, has: '%s' in host pattern '%s'",
+				ErrInvalidWildcard, label, pattern)
+		}
+	}
+
+	return labels, nil
+}
+
+// staticChild 获取静态子节点, 不存在时返回nil
+func (hn *HostNode[T]) staticChild(label string) *HostNode[T] {
+	for _, child := range hn.children {
+		if child.label == label {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// addChild 获取或新建子节点, 调用前需已检查冲突
+func (hn *HostNode[T]) addChild(label string) *HostNode[T] {
+	switch {
+	case label[0] == '*' && len(label) > 1:
+		if hn.catchAllChild == nil {
+			hn.catchAllChild = &HostNode[T]{label: label, nType: catchAll}
+		}
+		return hn.catchAllChild
+	case label[0] == ':' || label[0] == '*':
+		if hn.wildChild == nil {
+			hn.wildChild = &HostNode[T]{label: label, nType: param}
+		}
+		return hn.wildChild
+	}
+
+	if child := hn.staticChild(label); child != nil {
+		return child
+	}
+
+	child := &HostNode[T]{label: label, nType: static}
+	hn.children = append(hn.children, child)
+	return child
+}
+
+// GetValue 获取主机名对应的节点值, value.FullPath 为注册时的主机名模式
+// params不为nil时, 会先被清空, 匹配到的命名参数按模式中从左到右的顺序追加到params中并通过value.Params返回
+func (hn *HostNode[T]) GetValue(host string, params *Params) (value NodeValue[T]) {
+	if params != nil {
+		*params = (*params)[:0]
+	}
+
+	host = strings.TrimSuffix(host, hostSeparator)
+	if host == "" {
+		return
+	}
+
+	n := hn.match(host, params)
+	if n == nil {
+		if params != nil {
+			*params = (*params)[:0]
+		}
+		return
+	}
+
+	if params != nil && len(*params) > 0 {
+		// 匹配时从右向左记录参数, 恢复为模式中的顺序
+		ps := *params
+		for i, j := 0, len(ps)-1; i < j; i, j = i+1, j-1 {
+			ps[i], ps[j] = ps[j], ps[i]
+		}
+		value.Params = params
+	}
+
+	value.Data = n.data
+	value.FullPath = n.pattern
+	return
+}
+
+// match 从右向左匹配剩余的主机名, 返回注册了数据的节点
+func (hn *HostNode[T]) match(host string, params *Params) *HostNode[T] {
+	rest, label := "", host
+	if i := strings.LastIndex(host, hostSeparator); i >= 0 {
+		rest, label = host[:i], host[i+len(hostSeparator):]
+	}
+	if label == "" {
+		return nil
+	}
+
+	for _, child := range hn.children {
+		if strings.EqualFold(child.label, label) {
+			if n := child.next(rest, params); n != nil {
+				return n
+			}
+			break
+		}
+	}
+
+	if child := hn.wildChild; child != nil {
+		count := 0
+		if params != nil {
+			count = len(*params)
+			if child.label[0] == ':' {
+				*params = append(*params, Param{Key: child.label[1:], Value: label})
+			}
+		}
+
+		if n := child.next(rest, params); n != nil {
+			return n
+		}
+
+		if params != nil {
+			*params = (*params)[:count]
+		}
+	}
+
+	if child := hn.catchAllChild; child != nil && child.data != nil {
+		if params != nil {
+			*params = append(*params, Param{Key: child.label[1:], Value: host})
+		}
+		return child
+	}
+
+	return nil
+}
+
+// next 当前标签匹配成功后继续匹配剩余的主机名
+func (hn *HostNode[T]) next(rest string, params *Params) *HostNode[T] {
+	if rest == "" {
+		if hn.data != nil {
+			return hn
+		}
+		return nil
+	}
+
+	return hn.match(rest, params)
+}
@@ -0,0 +1,133 @@
+package tree
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHost(t *testing.T) {
+	patterns := []string{
+		"example.com",
+		"www.example.com",
+		":tenant.example.com",
+		"admin.:tenant.example.com",
+		"*.cdn.example.com",
+		"*sub.static.example.com",
+		"static.example.com",
+		":app.:region.cloud.example.org",
+		"api.us.cloud.example.org",
+	}
+
+	hn := &HostNode[string]{}
+	for i := range patterns {
+		hn.AddNode(patterns[i], &patterns[i])
+	}
+
+	tests := []struct {
+		host    string
+		pattern string
+		params  Params
+	}{
+		{"example.com", "example.com", nil},
+		{"EXAMPLE.com.", "example.com", nil},
+		{"www.example.com", "www.example.com", nil},
+		{"acme.example.com", ":tenant.example.com", Params{{"tenant", "acme"}}},
+		{"admin.acme.example.com", "admin.:tenant.example.com", Params{{"tenant", "acme"}}},
+		{"img.cdn.example.com", "*.cdn.example.com", nil},
+		{"cdn.example.com", ":tenant.example.com", Params{{"tenant", "cdn"}}},
+		{"static.example.com", "static.example.com", nil},
+		{"a.b.static.example.com", "*sub.static.example.com", Params{{"sub", "a.b"}}},
+		{"web.eu.cloud.example.org", ":app.:region.cloud.example.org", Params{{"app", "web"}, {"region", "eu"}}},
+		{"api.us.cloud.example.org", "api.us.cloud.example.org", nil},
+		// 静态标签匹配失败后回退到通配符
+		{"web.us.cloud.example.org", ":app.:region.cloud.example.org", Params{{"app", "web"}, {"region", "us"}}},
+		{"x.admin.acme.example.com", "", nil},
+		{"com", "", nil},
+		{"example.net", "", nil},
+		{"", "", nil},
+		{"a..example.com", "", nil},
+	}
+
+	params := make(Params, 0, 4)
+	for _, test := range tests {
+		v := hn.GetValue(test.host, &params)
+		if test.pattern == "" {
+			assert.Nil(t, v.Data, test.host)
+			assert.Nil(t, v.Params, test.host)
+			continue
+		}
+
+		if assert.NotNil(t, v.Data, test.host) {
+			assert.Equal(t, test.pattern, *v.Data)
+			assert.Equal(t, test.pattern, v.FullPath)
+		}
+		if test.params == nil {
+			assert.Nil(t, v.Params, test.host)
+		} else if assert.NotNil(t, v.Params, test.host) {
+			assert.Equal(t, test.params, *v.Params, test.host)
+		}
+	}
+
+	v := hn.GetValue("acme.example.com", nil)
+	if assert.NotNil(t, v.Data) {
+		assert.Equal(t, ":tenant.example.com", *v.Data)
+	}
+	assert.Nil(t, v.Params)
+}
+
+func TestHostTryAddNode(t *testing.T) {
+	data := "data"
+	hn := &HostNode[string]{}
+	assert.NoError(t, hn.TryAddNode(":tenant.example.com", &data))
+	assert.NoError(t, hn.TryAddNode("*all.example.com", &data))
+
+	assert.ErrorIs(t, hn.TryAddNode(":tenant.example.com", &data), ErrDuplicatePath)
+	assert.ErrorIs(t, hn.TryAddNode(":tenant.Example.com.", &data), ErrDuplicatePath)
+
+	var conflict *ConflictError
+	err := hn.TryAddNode("www.:name.example.com", nil)
+	if assert.True(t, errors.As(err, &conflict)) {
+		assert.Equal(t, ":name", conflict.Segment)
+		assert.Equal(t, ":tenant", conflict.Existing)
+		assert.Equal(t, ":tenant.example.com", conflict.Prefix)
+	}
+	assert.True(t, errors.As(hn.TryAddNode("*.example.com", nil), &conflict))
+	assert.True(t, errors.As(hn.TryAddNode("*rest.example.com", nil), &conflict))
+
+	for _, pattern := range []string{"", ".", "a..com", ":.example.com", "a.*rest.com", "a*.com", ":a:b.com"} {
+		assert.ErrorIs(t, hn.TryAddNode(pattern, nil), ErrInvalidWildcard, pattern)
+	}
+
+	// 失败的添加不会留下多余的节点
+	assert.Nil(t, hn.staticChild("com").staticChild("example").wildChild.staticChild("www"))
+	assert.Panics(t, func() { hn.AddNode("a..com", nil) })
+}
+
+func TestHostPath(t *testing.T) {
+	hn := &HostNode[PathNode[string]]{}
+	tenant, admin := &PathNode[string]{}, &PathNode[string]{}
+	hn.AddNode(":tenant.example.com", tenant)
+	hn.AddNode("admin.example.com", admin)
+
+	routes := []string{"/users/:id", "/dashboard"}
+	tenant.AddNode(routes[0], &routes[0])
+	admin.AddNode(routes[1], &routes[1])
+
+	hostParams, pathParams := make(Params, 0, 1), make(Params, 0, 1)
+	hv := hn.GetValue("acme.example.com", &hostParams)
+	if assert.NotNil(t, hv.Data) {
+		v := hv.Data.GetValue("/users/42", &pathParams, &[]skippedNode[string]{})
+		if assert.NotNil(t, v.Data) {
+			assert.Equal(t, "acme", hostParams.ByName("tenant"))
+			assert.Equal(t, "42", pathParams.ByName("id"))
+		}
+	}
+
+	hv = hn.GetValue("admin.example.com", &hostParams)
+	if assert.NotNil(t, hv.Data) {
+		assert.Nil(t, hv.Data.GetValue("/users/42", nil, &[]skippedNode[string]{}).Data)
+		assert.NotNil(t, hv.Data.GetValue("/dashboard", nil, &[]skippedNode[string]{}).Data)
+	}
+}
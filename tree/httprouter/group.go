@@ -0,0 +1,59 @@
+package httprouter
+
+import (
+	"net/http"
+
+	"github.com/liuxh-go/chopper/tree"
+)
+
+/*
+	路由分组, 注册方式与 Router 相同
+	ex:
+	api := r.Group("/api", logging)
+	api.HandleFunc(http.MethodGet, "/users/:id", showUser) // 注册 "/api/users/:id"
+*/
+
+// Group 路由分组, 基于 tree.RouteGroup
+type Group struct {
+	group *tree.RouteGroup[http.Handler]
+}
+
+// Group 新建路由分组, 中间件按添加顺序由外向内包装处理函数
+func (r *Router) Group(prefix string, middlewares ...tree.Middleware[http.Handler]) *Group {
+	return &Group{
+		group: tree.NewRouteGroup(func(method, path string, handler *http.Handler) {
+			r.Handle(method, path, *handler)
+		}).Group(prefix, middlewares...),
+	}
+}
+
+// Group 新建子分组, 规则同 tree.RouteGroup.Group
+func (g *Group) Group(prefix string, middlewares ...tree.Middleware[http.Handler]) *Group {
+	return &Group{
+		group: g.group.Group(prefix, middlewares...),
+	}
+}
+
+// Use 追加中间件, 只对之后注册的路径和新建的子分组生效
+func (g *Group) Use(middlewares ...tree.Middleware[http.Handler]) {
+	g.group.Use(middlewares...)
+}
+
+// Prefix 获取分组的完整前缀
+func (g *Group) Prefix() string {
+	return g.group.Prefix()
+}
+
+// Handle 注册处理函数, 实际注册的路径为分组前缀加path, 路径不合法或冲突时panic
+func (g *Group) Handle(method, path string, handler http.Handler) {
+	if handler == nil {
+		panic("handler must not be nil")
+	}
+
+	g.group.Handle(method, path, &handler)
+}
+
+// HandleFunc 注册处理函数, 规则同 Handle
+func (g *Group) HandleFunc(method, path string, handler http.HandlerFunc) {
+	g.Handle(method, path, handler)
+}
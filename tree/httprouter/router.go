@@ -0,0 +1,204 @@
+package httprouter
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/liuxh-go/chopper/tree"
+)
+
+/*
+	基于路径树的http.Handler
+	ex:
+	r := httprouter.New()
+	r.HandleFunc(http.MethodGet, "/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		id := httprouter.ParamsFromContext(req.Context()).ByName("id")
+		...
+	})
+	http.ListenAndServe(":8080", r)
+
+	静态路由的请求处理零分配; 带参数的路由每次匹配都会分配一个保存参数的上下文及 WithContext 生成的请求副本
+*/
+
+// paramsKey 路径参数在请求上下文中的键
+type paramsKey struct{}
+
+// ParamsFromContext 获取请求上下文中的路径参数, 没有参数时返回nil
+// 参数只在处理函数执行期间有效, 需要在处理函数返回后使用时应自行复制
+func ParamsFromContext(ctx context.Context) tree.Params {
+	if ps, ok := ctx.Value(paramsKey{}).(*tree.Params); ok {
+		return *ps
+	}
+
+	return nil
+}
+
+// Router 路由器, 按请求方法分别维护路径树
+// 所有路由需在开始处理请求之前注册完成
+type Router struct {
+	trees   *tree.Router[http.Handler]
+	methods []string
+
+	// RedirectTrailingSlash 路径不匹配但增减末尾的'/'后可以匹配时自动重定向
+	// GET请求使用301, 其他请求使用308
+	RedirectTrailingSlash bool
+	// HandleMethodNotAllowed 路径能被其他请求方法匹配时返回405并设置Allow头
+	HandleMethodNotAllowed bool
+	// HandleOPTIONS 自动响应未注册的OPTIONS请求
+	HandleOPTIONS bool
+
+	// NotFound 未匹配时的处理函数, 为nil时使用 http.NotFound
+	NotFound http.Handler
+	// MethodNotAllowed 返回405时的处理函数, 调用前已设置Allow头, 为nil时使用 http.Error
+	MethodNotAllowed http.Handler
+	// GlobalOPTIONS 自动响应OPTIONS请求时的处理函数, 调用前已设置Allow头, 为nil时只返回Allow头
+	GlobalOPTIONS http.Handler
+}
+
+// New 构造函数, 默认开启末尾'/'重定向、405和OPTIONS处理
+func New() *Router {
+	return &Router{
+		trees:                  tree.NewRouter[http.Handler](),
+		RedirectTrailingSlash:  true,
+		HandleMethodNotAllowed: true,
+		HandleOPTIONS:          true,
+	}
+}
+
+// Handle 注册处理函数, 路径不合法或冲突时panic
+func (r *Router) Handle(method, path string, handler http.Handler) {
+	if handler == nil {
+		panic("handler must not be nil")
+	}
+
+	r.trees.Handle(method, path, &handler)
+	r.addMethod(method)
+}
+
+// HandleNamed 以指定名称注册处理函数, 名称重复、路径不合法或冲突时panic
+func (r *Router) HandleNamed(name, method, path string, handler http.Handler) {
+	if handler == nil {
+		panic("handler must not be nil")
+	}
+
+	r.trees.HandleNamed(name, method, path, &handler)
+	r.addMethod(method)
+}
+
+// URL 根据路由名和参数生成URL, 规则同 tree.Router.URL
+func (r *Router) URL(name string, pairs ...string) (string, error) {
+	return r.trees.URL(name, pairs...)
+}
+
+// addMethod 记录已注册的请求方法, 保持有序
+func (r *Router) addMethod(method string) {
+	i := sort.SearchStrings(r.methods, method)
+	if i == len(r.methods) || r.methods[i] != method {
+		r.methods = append(r.methods, "")
+		copy(r.methods[i+1:], r.methods[i:])
+		r.methods[i] = method
+	}
+}
+
+// HandleFunc 注册处理函数, 路径不合法或冲突时panic
+func (r *Router) HandleFunc(method, path string, handler http.HandlerFunc) {
+	r.Handle(method, path, handler)
+}
+
+// ServeHTTP 实现http.Handler接口
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path := req.URL.Path
+
+	value := r.trees.Lookup(req.Method, path)
+	if value.Data != nil {
+		if value.Params != nil {
+			defer r.trees.PutParams(value.Params)
+			req = req.WithContext(context.WithValue(req.Context(), paramsKey{}, value.Params))
+		}
+
+		(*value.Data).ServeHTTP(w, req)
+		return
+	}
+
+	if value.Tsr && r.RedirectTrailingSlash && req.Method != http.MethodConnect && path != "/" {
+		code := http.StatusMovedPermanently
+		if req.Method != http.MethodGet {
+			code = http.StatusPermanentRedirect
+		}
+
+		if strings.HasSuffix(path, "/") {
+			path = path[:len(path)-1]
+		} else {
+			path += "/"
+		}
+		if req.URL.RawQuery != "" {
+			path += "?" + req.URL.RawQuery
+		}
+
+		http.Redirect(w, req, path, code)
+		return
+	}
+
+	if req.Method == http.MethodOptions && r.HandleOPTIONS {
+		if allow := r.allowed(path, http.MethodOptions); allow != "" {
+			w.Header().Set("Allow", allow)
+			if r.GlobalOPTIONS != nil {
+				r.GlobalOPTIONS.ServeHTTP(w, req)
+			}
+			return
+		}
+	} else if r.HandleMethodNotAllowed {
+		if allow := r.allowed(path, req.Method); allow != "" {
+			w.Header().Set("Allow", allow)
+			if r.MethodNotAllowed != nil {
+				r.MethodNotAllowed.ServeHTTP(w, req)
+			} else {
+				http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			}
+			return
+		}
+	}
+
+	if r.NotFound != nil {
+		r.NotFound.ServeHTTP(w, req)
+	} else {
+		http.NotFound(w, req)
+	}
+}
+
+// allowed 获取能匹配路径的请求方法, 以逗号分隔, 没有时返回空字符串
+// path 为"*"时返回所有已注册的请求方法
+func (r *Router) allowed(path, reqMethod string) string {
+	allowed := make([]string, 0, len(r.methods)+1)
+	for _, method := range r.methods {
+		if method == reqMethod {
+			continue
+		}
+
+		if path == "*" {
+			allowed = append(allowed, method)
+			continue
+		}
+
+		value := r.trees.Lookup(method, path)
+		r.trees.PutParams(value.Params)
+		if value.Data != nil {
+			allowed = append(allowed, method)
+		}
+	}
+
+	if len(allowed) == 0 {
+		return ""
+	}
+
+	if i := sort.SearchStrings(allowed, http.MethodOptions); r.HandleOPTIONS &&
+		(i == len(allowed) || allowed[i] != http.MethodOptions) {
+		allowed = append(allowed, "")
+		copy(allowed[i+1:], allowed[i:])
+		allowed[i] = http.MethodOptions
+	}
+
+	return strings.Join(allowed, ", ")
+}
@@ -0,0 +1,253 @@
+package httprouter
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/liuxh-go/chopper/tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRouter() *Router {
+	r := New()
+	write := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) {
+			_, _ = io.WriteString(w, body)
+		}
+	}
+
+	r.HandleFunc(http.MethodGet, "/", write("index"))
+	r.HandleFunc(http.MethodGet, "/users/", write("users"))
+	r.HandleFunc(http.MethodGet, "/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(w, "user "+ParamsFromContext(req.Context()).ByName("id"))
+	})
+	r.HandleFunc(http.MethodPut, "/users/:id", write("update"))
+	r.HandleFunc(http.MethodDelete, "/users/:id", write("delete"))
+	r.HandleFunc(http.MethodGet, "/files/*filepath", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(w, ParamsFromContext(req.Context()).ByName("filepath"))
+	})
+	r.HandleFunc(http.MethodPost, "/upload/", write("upload"))
+
+	return r
+}
+
+func serve(r http.Handler, method, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
+
+func TestRouter(t *testing.T) {
+	r := newTestRouter()
+
+	tests := []struct {
+		method, target string
+		code           int
+		body           string
+	}{
+		{http.MethodGet, "/", http.StatusOK, "index"},
+		{http.MethodGet, "/users/", http.StatusOK, "users"},
+		{http.MethodGet, "/users/42", http.StatusOK, "user 42"},
+		{http.MethodPut, "/users/42", http.StatusOK, "update"},
+		{http.MethodGet, "/files/a/b.txt", http.StatusOK, "/a/b.txt"},
+		{http.MethodGet, "/nothing", http.StatusNotFound, "404 page not found\n"},
+	}
+	for _, test := range tests {
+		w := serve(r, test.method, test.target)
+		assert.Equal(t, test.code, w.Code, test.target)
+		assert.Equal(t, test.body, w.Body.String(), test.target)
+	}
+
+	// 处理函数之外没有参数
+	assert.Nil(t, ParamsFromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context()))
+
+	assert.Panics(t, func() { r.Handle(http.MethodGet, "/nil", nil) })
+	assert.Panics(t, func() { r.HandleFunc(http.MethodGet, "/users/:name", func(http.ResponseWriter, *http.Request) {}) })
+
+	// sync.Pool 在 race 模式下会随机丢弃对象
+	if !raceEnabled {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/users/", nil)
+		allocs := testing.AllocsPerRun(100, func() {
+			w.Body.Reset()
+			r.ServeHTTP(w, req)
+		})
+		assert.Zero(t, allocs)
+	}
+}
+
+func TestRouterParamsAllocs(t *testing.T) {
+	r := New()
+	var id string
+	r.HandleFunc(http.MethodGet, "/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		id = ParamsFromContext(req.Context()).ByName("id")
+	})
+
+	// 带参数的路由每次请求分配一个上下文和 WithContext 生成的请求副本
+	// sync.Pool 在 race 模式下会随机丢弃对象
+	if !raceEnabled {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+		allocs := testing.AllocsPerRun(100, func() {
+			r.ServeHTTP(w, req)
+		})
+		assert.Equal(t, "42", id)
+		assert.LessOrEqual(t, allocs, float64(2))
+	}
+}
+
+func TestRouterRedirect(t *testing.T) {
+	r := newTestRouter()
+
+	tests := []struct {
+		method, target string
+		code           int
+		location       string
+	}{
+		{http.MethodGet, "/users", http.StatusMovedPermanently, "/users/"},
+		{http.MethodGet, "/users/42/", http.StatusMovedPermanently, "/users/42"},
+		{http.MethodGet, "/users?page=2", http.StatusMovedPermanently, "/users/?page=2"},
+		{http.MethodPost, "/upload", http.StatusPermanentRedirect, "/upload/"},
+	}
+	for _, test := range tests {
+		w := serve(r, test.method, test.target)
+		assert.Equal(t, test.code, w.Code, test.target)
+		assert.Equal(t, test.location, w.Header().Get("Location"), test.target)
+	}
+
+	r.RedirectTrailingSlash = false
+	assert.Equal(t, http.StatusNotFound, serve(r, http.MethodGet, "/users").Code)
+}
+
+func TestRouterMethodNotAllowed(t *testing.T) {
+	r := newTestRouter()
+
+	w := serve(r, http.MethodPost, "/users/42")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "DELETE, GET, OPTIONS, PUT", w.Header().Get("Allow"))
+
+	r.MethodNotAllowed = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	w = serve(r, http.MethodPost, "/users/42")
+	assert.Equal(t, http.StatusTeapot, w.Code)
+	assert.Equal(t, "DELETE, GET, OPTIONS, PUT", w.Header().Get("Allow"))
+
+	r.HandleMethodNotAllowed = false
+	assert.Equal(t, http.StatusNotFound, serve(r, http.MethodPost, "/users/42").Code)
+
+	r.NotFound = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	assert.Equal(t, http.StatusGone, serve(r, http.MethodGet, "/nothing").Code)
+}
+
+func TestRouterOptions(t *testing.T) {
+	r := newTestRouter()
+
+	w := serve(r, http.MethodOptions, "/users/42")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "DELETE, GET, OPTIONS, PUT", w.Header().Get("Allow"))
+
+	w = serve(r, http.MethodOptions, "*")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "DELETE, GET, OPTIONS, POST, PUT", w.Header().Get("Allow"))
+
+	assert.Equal(t, http.StatusNotFound, serve(r, http.MethodOptions, "/nothing").Code)
+
+	r.GlobalOPTIONS = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	w = serve(r, http.MethodOptions, "/upload/")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "OPTIONS, POST", w.Header().Get("Allow"))
+
+	// 显式注册的OPTIONS优先
+	r.HandleFunc(http.MethodOptions, "/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	assert.Equal(t, http.StatusAccepted, serve(r, http.MethodOptions, "/users/42").Code)
+
+	r.HandleOPTIONS = false
+	w = serve(r, http.MethodOptions, "/upload/")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "POST", w.Header().Get("Allow"))
+}
+
+func TestRouterGroup(t *testing.T) {
+	r := New()
+	header := func(value string) tree.Middleware[http.Handler] {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Add("X-Chain", value)
+				next.ServeHTTP(w, req)
+			})
+		}
+	}
+
+	api := r.Group("/api", header("api"))
+	v1 := api.Group("/v1", header("v1"))
+	assert.Equal(t, "/api/v1", v1.Prefix())
+	v1.HandleFunc(http.MethodGet, "/users/:id", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(w, "user "+ParamsFromContext(req.Context()).ByName("id"))
+	})
+	v1.Use(header("admin"))
+	v1.Handle(http.MethodDelete, "/users/:id", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	w := serve(r, http.MethodGet, "/api/v1/users/42")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "user 42", w.Body.String())
+	assert.Equal(t, []string{"api", "v1"}, w.Header().Values("X-Chain"))
+
+	w = serve(r, http.MethodDelete, "/api/v1/users/42")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{"api", "v1", "admin"}, w.Header().Values("X-Chain"))
+
+	w = serve(r, http.MethodPost, "/api/v1/users/42")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "DELETE, GET, OPTIONS", w.Header().Get("Allow"))
+
+	assert.Panics(t, func() { v1.Handle(http.MethodGet, "/nil", nil) })
+}
+
+func TestRouterURL(t *testing.T) {
+	r := New()
+	r.HandleNamed("user.show", http.MethodGet, "/users/:id", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(w, "user "+ParamsFromContext(req.Context()).ByName("id"))
+	}))
+
+	u, err := r.URL("user.show", "id", "a b")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "/users/a%20b", u)
+		assert.Equal(t, "user a b", serve(r, http.MethodGet, u).Body.String())
+	}
+
+	_, err = r.URL("user.show")
+	assert.ErrorIs(t, err, tree.ErrInvalidURLParams)
+	_, err = r.URL("user.show", "id", "a/b")
+	assert.ErrorIs(t, err, tree.ErrInvalidURLParams)
+	assert.Panics(t, func() { r.HandleNamed("user.edit", http.MethodGet, "/edit", nil) })
+
+	// 生成的URL经过请求解码后能匹配原路由并还原参数值
+	r.HandleNamed("post", http.MethodGet, "/posts/:slug/*file", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ps := ParamsFromContext(req.Context())
+		_, _ = io.WriteString(w, ps.ByName("slug")+"|"+ps.ByName("file"))
+	}))
+	for _, pairs := range [][]string{
+		{"slug", "a b", "file", "x/y z.txt"},
+		{"slug", "100%", "file", "/a?b#c"},
+		{"slug", "中文", "file", ""},
+	} {
+		u, err := r.URL("post", pairs...)
+		if assert.NoError(t, err, pairs) {
+			w := serve(r, http.MethodGet, u)
+			assert.Equal(t, http.StatusOK, w.Code, u)
+			assert.Equal(t, pairs[1]+"|/"+strings.TrimPrefix(pairs[3], "/"), w.Body.String(), u)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package tree
+
+import (
+	"fmt"
+
+	"github.com/liuxh-go/chopper/math"
+	"golang.org/x/exp/constraints"
+)
+
+/*
+	区间树, 基于红黑树按区间起点排序, 每个节点额外记录子树中最大的区间终点
+	区间均为闭区间, 查询重叠区间的时间复杂度为O(log n + k)
+	同一个区间可以插入多个值, 不会互相覆盖
+	ex:
+	it := NewIntervalTree[int, string]()
+	_ = it.Insert(9, 12, "meeting")
+	_ = it.Insert(9, 12, "interview")
+	it.Containing(10, func(iv Interval[int], v string) bool { ... }) // meeting, interview
+*/
+
+// Interval 闭区间[Lo, Hi]
+type Interval[K constraints.Ordered] struct {
+	Lo, Hi K
+}
+
+// Overlaps 是否与另一个区间重叠, 端点相同也算重叠
+func (iv Interval[K]) Overlaps(other Interval[K]) bool {
+	return iv.Lo <= other.Hi && other.Lo <= iv.Hi
+}
+
+// intervalEntry 区间树节点的值
+type intervalEntry[K constraints.Ordered, V any] struct {
+	// values 区间上的值, 按插入顺序排列
+	values []V
+	// max 子树中最大的区间终点
+	max K
+}
+
+// IntervalTree 区间树, 非并发安全
+// 相同的区间可以保存多个值, 遍历时同一区间的值按插入顺序依次返回
+type IntervalTree[K constraints.Ordered, V any] struct {
+	rb *RBTree[Interval[K], intervalEntry[K, V]]
+	// count 值的数量
+	count int
+}
+
+// NewIntervalTree 构造函数
+func NewIntervalTree[K constraints.Ordered, V any]() *IntervalTree[K, V] {
+	rb := NewRBTreeFunc[Interval[K], intervalEntry[K, V]](func(a, b Interval[K]) bool {
+		if a.Lo != b.Lo {
+			return a.Lo < b.Lo
+		}
+		return a.Hi < b.Hi
+	})
+	rb.augment = func(n *rbNode[Interval[K], intervalEntry[K, V]]) {
+		n.value.max = n.key.Hi
+		for _, child := range []*rbNode[Interval[K], intervalEntry[K, V]]{n.left, n.right} {
+			if child != nil {
+				n.value.max = math.Max(n.value.max, child.value.max)
+			}
+		}
+	}
+
+	return &IntervalTree[K, V]{
+		rb: rb,
+	}
+}
+
+// Len 获取值的数量, 同一区间上的多个值分别计数
+func (it *IntervalTree[K, V]) Len() int {
+	return it.count
+}
+
+// Insert 插入区间和值, 区间已存在时追加到该区间已有的值之后, lo大于hi时返回 ErrInvalidInterval
+func (it *IntervalTree[K, V]) Insert(lo, hi K, value V) error {
+	if hi < lo {
+		return fmt.Errorf("%w: [%v, %v]", ErrInvalidInterval, lo, hi)
+	}
+
+	iv := Interval[K]{Lo: lo, Hi: hi}
+	entry, _ := it.rb.Get(iv)
+	entry.values = append(entry.values, value)
+	it.rb.Put(iv, entry)
+	it.count++
+	return nil
+}
+
+// Get 获取区间上所有值的副本, 区间不存在时返回nil
+func (it *IntervalTree[K, V]) Get(lo, hi K) []V {
+	entry, _ := it.rb.Get(Interval[K]{Lo: lo, Hi: hi})
+	if len(entry.values) == 0 {
+		return nil
+	}
+
+	return append([]V(nil), entry.values...)
+}
+
+// Delete 删除区间及其上的所有值, 区间不存在时返回false
+func (it *IntervalTree[K, V]) Delete(lo, hi K) bool {
+	iv := Interval[K]{Lo: lo, Hi: hi}
+	entry, ok := it.rb.Get(iv)
+	if !ok {
+		return false
+	}
+
+	it.rb.Delete(iv)
+	it.count -= len(entry.values)
+	return true
+}
+
+// DeleteFunc 删除区间上match返回true的值, 区间上没有值时删除区间, 返回删除的值的数量
+func (it *IntervalTree[K, V]) DeleteFunc(lo, hi K, match func(value V) bool) int {
+	iv := Interval[K]{Lo: lo, Hi: hi}
+	entry, ok := it.rb.Get(iv)
+	if !ok {
+		return 0
+	}
+
+	values := make([]V, 0, len(entry.values))
+	for _, value := range entry.values {
+		if !match(value) {
+			values = append(values, value)
+		}
+	}
+
+	deleted := len(entry.values) - len(values)
+	switch {
+	case len(values) == 0:
+		it.rb.Delete(iv)
+	case deleted > 0:
+		entry.values = values
+		it.rb.Put(iv, entry)
+	}
+	it.count -= deleted
+	return deleted
+}
+
+// Ascend 按区间起点从小到大遍历, 起点相同时按终点排序, 同一区间的值按插入顺序, fn返回false时停止遍历
+func (it *IntervalTree[K, V]) Ascend(fn func(iv Interval[K], value V) bool) {
+	it.rb.Ascend(func(iv Interval[K], entry intervalEntry[K, V]) bool {
+		return eachValue(iv, entry.values, fn)
+	})
+}
+
+// Overlapping 按 Ascend 的顺序遍历与[lo, hi]重叠的区间, fn返回false时停止遍历
+func (it *IntervalTree[K, V]) Overlapping(lo, hi K, fn func(iv Interval[K], value V) bool) {
+	it.overlapping(it.rb.root, Interval[K]{Lo: lo, Hi: hi}, fn)
+}
+
+func (it *IntervalTree[K, V]) overlapping(n *rbNode[Interval[K], intervalEntry[K, V]], query Interval[K],
+	fn func(Interval[K], V) bool) bool {
+	// 子树中所有区间都在查询区间之前结束
+	if n == nil || n.value.max < query.Lo {
+		return true
+	}
+
+	if !it.overlapping(n.left, query, fn) {
+		return false
+	}
+
+	// 右子树的区间起点都不小于当前节点
+	if n.key.Lo > query.Hi {
+		return true
+	}
+
+	if n.key.Overlaps(query) && !eachValue(n.key, n.value.values, fn) {
+		return false
+	}
+
+	return it.overlapping(n.right, query, fn)
+}
+
+// Containing 按 Ascend 的顺序遍历包含point的区间, fn返回false时停止遍历
+func (it *IntervalTree[K, V]) Containing(point K, fn func(iv Interval[K], value V) bool) {
+	it.Overlapping(point, point, fn)
+}
+
+// eachValue 依次对区间上的值调用fn, fn返回false时停止并返回false
+func eachValue[K constraints.Ordered, V any](iv Interval[K], values []V, fn func(Interval[K], V) bool) bool {
+	for _, value := range values {
+		if !fn(iv, value) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,157 @@
+package tree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// checkIntervalMax 检查每个节点记录的最大终点, 返回子树中最大的终点
+func checkIntervalMax[V any](t *testing.T, n *rbNode[Interval[int], intervalEntry[int, V]]) int {
+	max := n.key.Hi
+	for _, child := range []*rbNode[Interval[int], intervalEntry[int, V]]{n.left, n.right} {
+		if child != nil {
+			if m := checkIntervalMax(t, child); m > max {
+				max = m
+			}
+		}
+	}
+	assert.Equal(t, max, n.value.max)
+
+	return max
+}
+
+func TestIntervalTree(t *testing.T) {
+	it := NewIntervalTree[int, string]()
+	it.Overlapping(0, 10, func(Interval[int], string) bool {
+		t.Fatal("empty tree")
+		return false
+	})
+
+	assert.NoError(t, it.Insert(15, 20, "a"))
+	assert.NoError(t, it.Insert(10, 30, "b"))
+	assert.NoError(t, it.Insert(17, 19, "c"))
+	assert.NoError(t, it.Insert(5, 20, "d"))
+	assert.NoError(t, it.Insert(12, 15, "e"))
+	assert.NoError(t, it.Insert(30, 40, "f"))
+	assert.NoError(t, it.Insert(30, 40, "g"))
+	assert.ErrorIs(t, it.Insert(2, 1, "x"), ErrInvalidInterval)
+	assert.Equal(t, 7, it.Len())
+
+	// 相同的区间不会互相覆盖
+	assert.Equal(t, []string{"f", "g"}, it.Get(30, 40))
+	assert.Nil(t, it.Get(30, 41))
+
+	collect := func(query func(fn func(Interval[int], string) bool)) []string {
+		var result []string
+		query(func(_ Interval[int], v string) bool {
+			result = append(result, v)
+			return true
+		})
+		return result
+	}
+	overlapping := func(lo, hi int) []string {
+		return collect(func(fn func(Interval[int], string) bool) { it.Overlapping(lo, hi, fn) })
+	}
+	containing := func(p int) []string {
+		return collect(func(fn func(Interval[int], string) bool) { it.Containing(p, fn) })
+	}
+
+	assert.Equal(t, []string{"d", "b", "e", "a"}, overlapping(14, 16))
+	assert.Equal(t, []string{"b", "f", "g"}, overlapping(21, 30))
+	assert.Equal(t, []string{"f", "g"}, containing(40))
+	assert.Nil(t, overlapping(41, 50))
+	assert.Nil(t, overlapping(0, 4))
+	assert.Equal(t, []string{"d"}, containing(5))
+	assert.Equal(t, []string{"d", "b", "a", "c"}, containing(18))
+	assert.Equal(t, []string{"d", "b", "e", "a", "c", "f", "g"}, collect(it.Ascend))
+
+	assert.True(t, it.Delete(10, 30))
+	assert.False(t, it.Delete(10, 30))
+	assert.Equal(t, []string{"f", "g"}, overlapping(21, 30))
+	assert.Equal(t, 6, it.Len())
+
+	// 只删除区间上的部分值
+	isF := func(v string) bool { return v == "f" }
+	assert.Equal(t, 1, it.DeleteFunc(30, 40, isF))
+	assert.Equal(t, 0, it.DeleteFunc(30, 40, isF))
+	assert.Equal(t, 0, it.DeleteFunc(31, 40, isF))
+	assert.Equal(t, []string{"g"}, overlapping(21, 30))
+	assert.Equal(t, 1, it.DeleteFunc(30, 40, func(string) bool { return true }))
+	assert.Nil(t, overlapping(21, 30))
+	assert.Equal(t, 4, it.Len())
+	checkIntervalMax(t, it.rb.root)
+
+	// 提前终止
+	count := 0
+	it.Overlapping(0, 100, func(Interval[int], string) bool {
+		count++
+		return false
+	})
+	assert.Equal(t, 1, count)
+}
+
+func TestIntervalTreeRandom(t *testing.T) {
+	it := NewIntervalTree[int, int]()
+	intervals := map[Interval[int]][]int{}
+	count := 0
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		lo := r.Intn(500)
+		iv := Interval[int]{Lo: lo, Hi: lo + r.Intn(20)}
+		switch r.Intn(4) {
+		case 0:
+			_, exists := intervals[iv]
+			assert.Equal(t, exists, it.Delete(iv.Lo, iv.Hi))
+			count -= len(intervals[iv])
+			delete(intervals, iv)
+		case 1:
+			odd := func(v int) bool { return v%2 == 1 }
+			var kept []int
+			for _, v := range intervals[iv] {
+				if !odd(v) {
+					kept = append(kept, v)
+				}
+			}
+			assert.Equal(t, len(intervals[iv])-len(kept), it.DeleteFunc(iv.Lo, iv.Hi, odd))
+			count -= len(intervals[iv]) - len(kept)
+			if kept == nil {
+				delete(intervals, iv)
+			} else {
+				intervals[iv] = kept
+			}
+		default:
+			assert.NoError(t, it.Insert(iv.Lo, iv.Hi, i))
+			intervals[iv] = append(intervals[iv], i)
+			count++
+		}
+	}
+	assert.Equal(t, count, it.Len())
+	checkIntervalMax(t, it.rb.root)
+	checkRBTree(t, it.rb, it.rb.root)
+
+	for i := 0; i < 100; i++ {
+		lo := r.Intn(1100)
+		query := Interval[int]{Lo: lo, Hi: lo + r.Intn(20)}
+
+		want := map[Interval[int]][]int{}
+		for iv, values := range intervals {
+			if iv.Overlaps(query) {
+				want[iv] = values
+			}
+		}
+
+		got := map[Interval[int]][]int{}
+		var last *Interval[int]
+		it.Overlapping(query.Lo, query.Hi, func(iv Interval[int], v int) bool {
+			if last != nil {
+				assert.True(t, last.Lo < iv.Lo || last.Lo == iv.Lo && last.Hi <= iv.Hi)
+			}
+			last = &iv
+			got[iv] = append(got[iv], v)
+			return true
+		})
+		assert.Equal(t, want, got)
+	}
+}
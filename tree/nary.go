@@ -0,0 +1,221 @@
+package tree
+
+import "fmt"
+
+/*
+	多叉树, 节点记录父节点和有序的子节点列表
+	ex:
+	root := NewNaryNode("company")
+	dev := NewNaryNode("dev")
+	_ = root.AddChild(dev)
+	dev.Path() // [company dev]
+*/
+
+// NaryNode 多叉树节点, 非并发安全
+type NaryNode[T any] struct {
+	node     *Node[T]
+	parent   *NaryNode[T]
+	children []*NaryNode[T]
+}
+
+// NewNaryNode 新建多叉树节点
+func NewNaryNode[T any](t T) *NaryNode[T] {
+	return &NaryNode[T]{
+		node: &Node[T]{data: t},
+	}
+}
+
+// Data 获取节点数据
+func (nn *NaryNode[T]) Data() T {
+	return nn.node.data
+}
+
+// Parent 获取父节点, 根节点返回nil
+func (nn *NaryNode[T]) Parent() *NaryNode[T] {
+	return nn.parent
+}
+
+// Children 获取子节点列表的副本
+func (nn *NaryNode[T]) Children() []*NaryNode[T] {
+	return append([]*NaryNode[T](nil), nn.children...)
+}
+
+// Root 获取所在树的根节点
+func (nn *NaryNode[T]) Root() *NaryNode[T] {
+	root := nn
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	return root
+}
+
+// Depth 获取节点深度, 根节点为0
+func (nn *NaryNode[T]) Depth() int {
+	depth := 0
+	for n := nn.parent; n != nil; n = n.parent {
+		depth++
+	}
+
+	return depth
+}
+
+// isAncestorOf 是否为other的祖先或other本身
+func (nn *NaryNode[T]) isAncestorOf(other *NaryNode[T]) bool {
+	for n := other; n != nil; n = n.parent {
+		if n == nn {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddChild 将child及其子树添加为最后一个子节点, child已有父节点时先从原位置移除
+// child为当前节点或其祖先时返回 ErrCycle
+func (nn *NaryNode[T]) AddChild(child *NaryNode[T]) error {
+	if child.isAncestorOf(nn) {
+		return fmt.Errorf("%w: node can not be added under itself or its descendant", ErrCycle)
+	}
+
+	child.Remove()
+	child.parent = nn
+	nn.children = append(nn.children, child)
+	return nil
+}
+
+// Remove 将当前节点及其子树从父节点中移除, 当前节点成为新的根节点
+func (nn *NaryNode[T]) Remove() {
+	parent := nn.parent
+	if parent == nil {
+		return
+	}
+
+	for i, child := range parent.children {
+		if child == nn {
+			parent.children = append(parent.children[:i], parent.children[i+1:]...)
+			break
+		}
+	}
+	nn.parent = nil
+}
+
+// Move 将当前节点及其子树移动到newParent下, 规则同 AddChild
+func (nn *NaryNode[T]) Move(newParent *NaryNode[T]) error {
+	return newParent.AddChild(nn)
+}
+
+// Ancestors 获取所有祖先节点, 从父节点到根节点
+func (nn *NaryNode[T]) Ancestors() []*NaryNode[T] {
+	var ancestors []*NaryNode[T]
+	for n := nn.parent; n != nil; n = n.parent {
+		ancestors = append(ancestors, n)
+	}
+
+	return ancestors
+}
+
+// Walk 先序遍历当前节点及其子树, depth为相对当前节点的深度, fn返回false时停止遍历
+func (nn *NaryNode[T]) Walk(fn func(n *NaryNode[T], depth int) bool) {
+	type frame struct {
+		n     *NaryNode[T]
+		depth int
+	}
+
+	stack := []frame{{n: nn}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !fn(f.n, f.depth) {
+			return
+		}
+
+		for i := len(f.n.children) - 1; i >= 0; i-- {
+			stack = append(stack, frame{n: f.n.children[i], depth: f.depth + 1})
+		}
+	}
+}
+
+// Descendants 获取所有后代节点, 按先序遍历的顺序排列, 不包含当前节点
+func (nn *NaryNode[T]) Descendants() []*NaryNode[T] {
+	var descendants []*NaryNode[T]
+	nn.Walk(func(n *NaryNode[T], _ int) bool {
+		if n != nn {
+			descendants = append(descendants, n)
+		}
+		return true
+	})
+
+	return descendants
+}
+
+// Path 获取从根节点到当前节点的数据
+func (nn *NaryNode[T]) Path() []T {
+	path := make([]T, nn.Depth()+1)
+	i := len(path) - 1
+	for n := nn; n != nil; n = n.parent {
+		path[i] = n.node.data
+		i--
+	}
+
+	return path
+}
+
+// BuildNaryTree 根据扁平的记录构建多叉树, 返回按记录顺序排列的根节点
+// id获取记录的唯一标识, parentID获取父记录的标识, 返回false时为根节点; 子节点按记录顺序排列
+// 标识重复时返回 ErrDuplicateID, 父记录不存在时返回 ErrOrphan, 存在环时返回 ErrCycle
+func BuildNaryTree[K comparable, T any](records []T, id func(T) K, parentID func(T) (K, bool)) ([]*NaryNode[T], error) {
+	nodes := make(map[K]*NaryNode[T], len(records))
+	list := make([]*NaryNode[T], len(records))
+	for i, record := range records {
+		k := id(record)
+		if _, exists := nodes[k]; exists {
+			return nil, fmt.Errorf("%w: %v", ErrDuplicateID, k)
+		}
+
+		list[i] = NewNaryNode(record)
+		nodes[k] = list[i]
+	}
+
+	var roots []*NaryNode[T]
+	for i, record := range records {
+		pk, ok := parentID(record)
+		if !ok {
+			roots = append(roots, list[i])
+			continue
+		}
+
+		parent, exists := nodes[pk]
+		if !exists {
+			return nil, fmt.Errorf("%w: parent %v of %v does not exist", ErrOrphan, pk, id(record))
+		}
+
+		list[i].parent = parent
+		parent.children = append(parent.children, list[i])
+	}
+
+	// 从根节点无法到达的节点一定在环上或挂在环下
+	reached := make(map[*NaryNode[T]]bool, len(records))
+	for _, root := range roots {
+		root.Walk(func(n *NaryNode[T], _ int) bool {
+			reached[n] = true
+			return true
+		})
+	}
+
+	for _, n := range list {
+		if reached[n] {
+			continue
+		}
+
+		// 沿父节点向上, 第一个重复经过的节点在环上
+		visited := make(map[*NaryNode[T]]bool)
+		for !visited[n] {
+			visited[n] = true
+			n = n.parent
+		}
+		return nil, fmt.Errorf("%w: %v is its own ancestor", ErrCycle, id(n.node.data))
+	}
+
+	return roots, nil
+}
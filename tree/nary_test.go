@@ -0,0 +1,96 @@
+package tree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// naryData 获取节点列表的数据
+func naryData[T any](list []*NaryNode[T]) []T {
+	result := make([]T, 0, len(list))
+	for _, n := range list {
+		result = append(result, n.Data())
+	}
+
+	return result
+}
+
+func TestNary(t *testing.T) {
+	root := NewNaryNode("company")
+	dev, ops := NewNaryNode("dev"), NewNaryNode("ops")
+	backend, frontend := NewNaryNode("backend"), NewNaryNode("frontend")
+	assert.NoError(t, root.AddChild(dev))
+	assert.NoError(t, root.AddChild(ops))
+	assert.NoError(t, dev.AddChild(backend))
+	assert.NoError(t, dev.AddChild(frontend))
+
+	assert.Equal(t, []string{"dev", "ops"}, naryData(root.Children()))
+	assert.Same(t, dev, backend.Parent())
+	assert.Nil(t, root.Parent())
+	assert.Same(t, root, frontend.Root())
+	assert.Equal(t, 2, frontend.Depth())
+	assert.Equal(t, 0, root.Depth())
+	assert.Equal(t, []string{"company", "dev", "frontend"}, frontend.Path())
+	assert.Equal(t, []string{"dev", "company"}, naryData(frontend.Ancestors()))
+	assert.Nil(t, root.Ancestors())
+	assert.Equal(t, []string{"dev", "backend", "frontend", "ops"}, naryData(root.Descendants()))
+
+	depths := map[string]int{}
+	root.Walk(func(n *NaryNode[string], depth int) bool {
+		depths[n.Data()] = depth
+		return n.Data() != "frontend"
+	})
+	assert.Equal(t, map[string]int{"company": 0, "dev": 1, "backend": 2, "frontend": 2}, depths)
+
+	// 移动子树
+	assert.NoError(t, frontend.Move(ops))
+	assert.Equal(t, []string{"backend"}, naryData(dev.Children()))
+	assert.Equal(t, []string{"company", "ops", "frontend"}, frontend.Path())
+
+	// 不能移动到自身或后代下
+	assert.ErrorIs(t, root.Move(frontend), ErrCycle)
+	assert.ErrorIs(t, dev.AddChild(dev), ErrCycle)
+	assert.Same(t, root, frontend.Root())
+
+	dev.Remove()
+	assert.Nil(t, dev.Parent())
+	assert.Equal(t, []string{"ops", "frontend"}, naryData(root.Descendants()))
+	assert.Equal(t, []string{"dev", "backend"}, backend.Path())
+	dev.Remove()
+}
+
+func TestBuildNaryTree(t *testing.T) {
+	type record struct {
+		id, parent int
+	}
+	id := func(r record) int { return r.id }
+	parentID := func(r record) (int, bool) { return r.parent, r.parent != 0 }
+
+	roots, err := BuildNaryTree([]record{
+		{4, 2}, {1, 0}, {2, 1}, {3, 1}, {5, 0}, {6, 5},
+	}, id, parentID)
+	assert.NoError(t, err)
+	if assert.Len(t, roots, 2) {
+		assert.Equal(t, 1, roots[0].Data().id)
+		assert.Equal(t, []record{{2, 1}, {4, 2}, {3, 1}}, naryData(roots[0].Descendants()))
+		assert.Equal(t, []record{{6, 5}}, naryData(roots[1].Descendants()))
+	}
+
+	_, err = BuildNaryTree([]record{{1, 0}, {2, 9}}, id, parentID)
+	assert.ErrorIs(t, err, ErrOrphan)
+
+	_, err = BuildNaryTree([]record{{1, 0}, {1, 0}}, id, parentID)
+	assert.ErrorIs(t, err, ErrDuplicateID)
+
+	_, err = BuildNaryTree([]record{{1, 0}, {2, 4}, {3, 2}, {4, 3}, {5, 3}}, id, parentID)
+	assert.ErrorIs(t, err, ErrCycle)
+	assert.Contains(t, err.Error(), "2 is its own ancestor")
+
+	_, err = BuildNaryTree([]record{{1, 1}}, id, parentID)
+	assert.ErrorIs(t, err, ErrCycle)
+
+	roots, err = BuildNaryTree(nil, id, parentID)
+	assert.NoError(t, err)
+	assert.Empty(t, roots)
+}
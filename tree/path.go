@@ -1,7 +1,11 @@
 package tree
 
 import (
+	"fmt"
+	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/liuxh-go/chopper/bytesconv"
 	"github.com/liuxh-go/chopper/math"
@@ -13,13 +17,47 @@ import (
 	1->(1)2->(12)3
 		  |->(12)4
 	2->(2)1->(21)3
+
+	支持的通配符:
+	:name     命名参数, 匹配到下一个'/'为止的单个路径段
+	*name     全匹配, 匹配剩余的全部路径, 只能位于路径末尾
+	:name<c>  带约束的命名参数, 参数值满足约束c时才匹配, 详见 path_constraint.go
+
+	分隔符和通配符标识可以通过 NewPathNode 的配置项修改, 如"metrics.http.*rest"
 */
 
+// Param 路径参数, 由参数名和匹配到的值组成
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Params 路径参数列表, 顺序与路径中通配符出现的顺序一致
+type Params []Param
+
+// Get 获取第一个名称匹配的参数值
+func (ps Params) Get(name string) (string, bool) {
+	for _, entry := range ps {
+		if entry.Key == name {
+			return entry.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// ByName 获取第一个名称匹配的参数值, 不存在时返回空字符串
+func (ps Params) ByName(name string) (va string) {
+	va, _ = ps.Get(name)
+	return
+}
+
 type nodeType uint8
 
 const (
 	static nodeType = iota
 	root
+	param
 	catchAll
 )
 
@@ -33,18 +71,43 @@ type PathNode[T any] struct {
 	childList []*PathNode[T]
 	fullPath  string
 	data      *T
+	cfg       *pathConfig
+	// match 命名参数的约束, 为nil时不限制
+	match func(string) bool
 }
 
-// AddNode 添加节点
+// AddNode 添加节点, 路径不合法或与已有路径冲突时panic
 func (pn *PathNode[T]) AddNode(path string, t *T) {
+	if err := pn.TryAddNode(path, t); err != nil {
+		panic(err)
+	}
+}
+
+// TryAddNode 添加节点, 路径不合法或与已有路径冲突时返回错误, 已注册的数据不受影响
+// 错误可通过 errors.Is 判断 ErrDuplicatePath、ErrInvalidWildcard, 或通过 errors.As 获取 *ConflictError
+func (pn *PathNode[T]) TryAddNode(path string, t *T) (err error) {
+	cfg := pn.config()
+	if err = cfg.validatePath(path); err != nil {
+		return
+	}
+
 	fullPath := path
 	pn.priority++
 
+	// 记录沿途增加过优先级的节点, 失败时回滚
+	visited := []*PathNode[T]{pn}
+	defer func() {
+		if err != nil {
+			for _, n := range visited {
+				n.priority--
+			}
+		}
+	}()
+
 	// empty tree
 	if len(pn.path) == 0 && len(pn.childList) == 0 {
-		pn.insertChild(path, fullPath, t)
 		pn.nType = root
-		return
+		return pn.insertChild(cfg, path, fullPath, t)
 	}
 
 	parentFullPathIndex := 0
@@ -80,18 +143,28 @@ walk:
 			path = path[i:]
 			c := path[0]
 
+			// separator after param
+			if pn.nType == param && strings.HasPrefix(path, cfg.separator) && len(pn.childList) == 1 {
+				parentFullPathIndex += len(pn.path)
+				pn = pn.childList[0]
+				pn.priority++
+				visited = append(visited, pn)
+				continue walk
+			}
+
 			// Check if a child with the next path byte exists
 			for i, max := 0, len(pn.indices); i < max; i++ {
 				if c == pn.indices[i] {
 					parentFullPathIndex += len(pn.path)
 					i = pn.incrementChildPrio(i)
 					pn = pn.childList[i]
+					visited = append(visited, pn)
 					continue walk
 				}
 			}
 
 			// insert node
-			if c != '*' && pn.nType != catchAll {
+			if !cfg.isWildcard(c) && pn.nType != catchAll {
 				pn.indices += bytesconv.BytesToString([]byte{c})
 				child := &PathNode[T]{
 					fullPath: fullPath,
@@ -100,38 +173,48 @@ walk:
 				pn.incrementChildPrio(len(pn.indices) - 1)
 				pn = child
 			} else if pn.wildChild {
-				pn = pn.childList[len(pn.childList)-1]
-				pn.priority++
+				wildList := pn.wildChildList()
+				for _, wild := range wildList {
+					// Check if the wildcard matches
+					if len(path) >= len(wild.path) && wild.path == path[:len(wild.path)] &&
+						// Adding a child to a catchAll is not possible
+						wild.nType != catchAll &&
+						// Check for longer wildcard, e.g. :name and :names
+						(len(wild.path) >= len(path) || strings.HasPrefix(path[len(wild.path):], cfg.separator)) {
+						pn = wild
+						pn.priority++
+						visited = append(visited, pn)
+						continue walk
+					}
+				}
 
-				// Check if the wildcard matches
-				if len(path) >= len(pn.path) && pn.path == path[:len(pn.path)] &&
-					// Adding a child to a catchAll is not possible
-					pn.nType != catchAll &&
-					// Check for longer wildcard, e.g. :name and :names
-					(len(pn.path) >= len(path) || path[len(pn.path)] == '/') {
-					continue walk
+				// 约束不同的命名参数可以共存
+				wildcard, _, _ := cfg.findWildcard(path)
+				existing := canAddWildChild(cfg, wildList, wildcard)
+				if existing == nil {
+					return pn.insertChild(cfg, path, fullPath, t)
 				}
 
 				// Wildcard conflict
 				pathSeg := path
-				if pn.nType != catchAll {
-					pathSeg = strings.SplitN(pathSeg, "/", 2)[0]
+				if existing.nType != catchAll {
+					pathSeg = strings.SplitN(pathSeg, cfg.separator, 2)[0]
+				}
+				return &ConflictError{
+					Segment:  pathSeg,
+					Path:     fullPath,
+					Existing: existing.path,
+					Prefix:   fullPath[:strings.Index(fullPath, pathSeg)] + existing.path,
+					Wildcard: true,
 				}
-				prefix := fullPath[:strings.Index(fullPath, pathSeg)] + pn.path
-				panic("'" + pathSeg +
-					"' in new path '" + fullPath +
-					"' conflicts with existing wildcard '" + pn.path +
-					"' in existing prefix '" + prefix +
-					"'")
 			}
 
-			pn.insertChild(path, fullPath, t)
-			return
+			return pn.insertChild(cfg, path, fullPath, t)
 		}
 
 		// Otherwise add handle to current node
 		if pn.data != nil {
-			panic("data are already registered for path '" + fullPath + "'")
+			return fmt.Errorf("%w '%s'", ErrDuplicatePath, fullPath)
 		}
 		pn.data = t
 		pn.fullPath = fullPath
@@ -139,21 +222,37 @@ walk:
 	}
 }
 
-func findWildcard(path string) (wildcard string, i int, valid bool) {
+// findWildcard 查找路径中的第一个通配符段并检查名称是否合法, 未找到时i返回-1
+func (cfg *pathConfig) findWildcard(path string) (wildcard string, i int, valid bool) {
 	// Find start
 	for start, c := range []byte(path) {
-		// A wildcard starts with '*' (catch-all)
-		if c != '*' {
+		// A wildcard starts with the param or the catch-all marker
+		if !cfg.isWildcard(c) {
 			continue
 		}
 
-		// Find end and check for invalid characters
+		// Find end and check for invalid characters, skipping the constraint
 		valid = true
-		for end, c := range []byte(path[start+1:]) {
-			switch c {
-			case '/':
-				return path[start : start+1+end], start, valid
-			case '*':
+		depth := 0
+		for end := start + 1; end < len(path); end++ {
+			switch path[end] {
+			case constraintStart:
+				depth++
+			case constraintEnd:
+				if depth > 0 {
+					depth--
+					continue
+				}
+			}
+			if depth > 0 {
+				continue
+			}
+
+			if strings.HasPrefix(path[end:], cfg.separator) {
+				return path[start:end], start, valid
+			}
+
+			if cfg.isWildcard(path[end]) {
 				valid = false
 			}
 		}
@@ -162,43 +261,152 @@ func findWildcard(path string) (wildcard string, i int, valid bool) {
 	return "", -1, false
 }
 
-func (pn *PathNode[T]) insertChild(path, fullPath string, t *T) {
+// canAddWildChild 检查新的通配符能否与已有的通配符子节点共存, 不能时返回冲突的节点
+// 只有命名参数之间可以共存, 且约束各不相同, 无约束的命名参数最多一个
+func canAddWildChild[T any](cfg *pathConfig, wildList []*PathNode[T], wildcard string) *PathNode[T] {
+	if wildcard == "" || wildcard[0] != cfg.param {
+		return wildList[0]
+	}
+
+	_, constraint, _ := splitParam(wildcard)
+	for _, wild := range wildList {
+		if wild.nType != param {
+			return wild
+		}
+
+		if _, c, _ := splitParam(wild.path); c == constraint {
+			return wild
+		}
+	}
+
+	return nil
+}
+
+// validatePath 检查路径中通配符的写法是否合法
+func (cfg *pathConfig) validatePath(fullPath string) error {
+	path := fullPath
+	offset := 0
 	for {
-		wildcard, i, valid := findWildcard(path)
+		wildcard, i, valid := cfg.findWildcard(path)
 		if i < 0 {
-			break
+			return nil
 		}
 
-		// The wildcard name must only contain one '*' character
+		// The wildcard name must only contain one wildcard marker
 		if !valid {
-			panic("only one wildcard per path segment is allowed, has: '" +
-				wildcard + "' in path '" + fullPath + "'")
+			return fmt.Errorf("%w: only one wildcard per path segment is allowed, has: '%s' in path '%s'",
+				ErrInvalidWildcard, wildcard, fullPath)
 		}
 
 		// check if the wildcard has a name
-		if len(wildcard) < 2 {
-			panic("wildcards must be named with a non-empty name in path '" + fullPath + "'")
+		if len(wildcard) < 2 || wildcard[1] == constraintStart {
+			return fmt.Errorf("%w: wildcards must be named with a non-empty name in path '%s'",
+				ErrInvalidWildcard, fullPath)
 		}
 
-		if i+len(wildcard) != len(path) {
-			panic("catch-all routes are only allowed at the end of the path in path '" + fullPath + "'")
+		if wildcard[0] == cfg.param {
+			_, constraint, ok := splitParam(wildcard)
+			if !ok || (constraint == "" && strings.IndexByte(wildcard, constraintStart) >= 0) {
+				return fmt.Errorf("%w: malformed constraint in '%s' in path '%s'",
+					ErrInvalidWildcard, wildcard, fullPath)
+			}
+
+			// 参数值在分隔符处截断, 包含分隔符的约束永远无法匹配
+			if strings.Contains(constraint, cfg.separator) {
+				return fmt.Errorf("%w: constraint in '%s' must not contain '%s' in path '%s'",
+					ErrInvalidWildcard, wildcard, cfg.separator, fullPath)
+			}
+
+			if _, err := compileConstraint(constraint); err != nil {
+				return fmt.Errorf("%w in path '%s'", err, fullPath)
+			}
 		}
 
-		if len(pn.path) > 0 && pn.path[len(pn.path)-1] == '/' {
-			pathSeg := strings.SplitN(pn.childList[0].path, "/", 2)[0]
-			panic("catch-all wildcard '" + path +
-				"' in new path '" + fullPath +
-				"' conflicts with existing path segment '" + pathSeg +
-				"' in existing prefix '" + pn.path + pathSeg +
-				"'")
+		if wildcard[0] == cfg.catchAll {
+			if strings.IndexByte(wildcard, constraintStart) >= 0 {
+				return fmt.Errorf("%w: catch-all routes can not have a constraint in path '%s'",
+					ErrInvalidWildcard, fullPath)
+			}
+
+			if i+len(wildcard) != len(path) {
+				return fmt.Errorf("%w: catch-all routes are only allowed at the end of the path in path '%s'",
+					ErrInvalidWildcard, fullPath)
+			}
+
+			if !strings.HasSuffix(fullPath[:offset+i], cfg.separator) {
+				return fmt.Errorf("%w: no %s before catch-all in path '%s'",
+					ErrInvalidWildcard, cfg.separator, fullPath)
+			}
+		}
+
+		offset += i + len(wildcard)
+		path = path[i+len(wildcard):]
+	}
+}
+
+// insertChild 插入子节点, 路径需已通过 validatePath 检查
+func (pn *PathNode[T]) insertChild(cfg *pathConfig, path, fullPath string, t *T) error {
+	for {
+		wildcard, i, _ := cfg.findWildcard(path)
+		if i < 0 {
+			break
+		}
+
+		if wildcard[0] == cfg.param { // param
+			if i > 0 {
+				// Insert prefix before the current wildcard
+				pn.path = path[:i]
+				path = path[i:]
+			}
+
+			_, constraint, _ := splitParam(wildcard)
+			match, _ := compileConstraint(constraint)
+			child := &PathNode[T]{
+				nType:    param,
+				path:     wildcard,
+				fullPath: fullPath,
+				match:    match,
+			}
+			pn.addWildChild(child)
+			pn = child
+			pn.priority++
+
+			// if the path doesn't end with the wildcard, then there
+			// will be another subpath starting with the separator
+			if len(wildcard) < len(path) {
+				path = path[len(wildcard):]
+
+				child := &PathNode[T]{
+					priority: 1,
+					fullPath: fullPath,
+				}
+				pn.addNode(child)
+				pn = child
+				continue
+			}
+
+			// Otherwise we're done. Insert the data in the new leaf
+			pn.data = t
+			return nil
 		}
 
-		// currently fixed width 1 for '/'
-		i--
-		if path[i] != '/' {
-			panic("no / before catch-all in path '" + fullPath + "'")
+		// catchAll
+		if strings.HasSuffix(pn.path, cfg.separator) {
+			pathSeg := ""
+			if len(pn.childList) > 0 {
+				pathSeg = strings.SplitN(pn.childList[0].path, cfg.separator, 2)[0]
+			}
+			return &ConflictError{
+				Segment:  path,
+				Path:     fullPath,
+				Existing: pathSeg,
+				Prefix:   fullPath[:len(fullPath)-len(path)] + pathSeg,
+			}
 		}
 
+		// the separator may have been split into the parent node
+		i = math.Max(i-len(cfg.separator), 0)
+
 		pn.path = path[:i]
 
 		child := &PathNode[T]{
@@ -208,7 +416,7 @@ func (pn *PathNode[T]) insertChild(path, fullPath string, t *T) {
 		}
 
 		pn.addNode(child)
-		pn.indices = string('/')
+		pn.indices = path[i : i+1]
 		pn = child
 		pn.priority++
 
@@ -221,12 +429,13 @@ func (pn *PathNode[T]) insertChild(path, fullPath string, t *T) {
 		}
 		pn.childList = []*PathNode[T]{child}
 
-		return
+		return nil
 	}
 
 	pn.path = path
 	pn.data = t
 	pn.fullPath = fullPath
+	return nil
 }
 
 func (pn *PathNode[T]) incrementChildPrio(pos int) int {
@@ -250,13 +459,42 @@ func (pn *PathNode[T]) incrementChildPrio(pos int) int {
 
 func (pn *PathNode[T]) addNode(child *PathNode[T]) {
 	if pn.wildChild && len(pn.childList) > 0 {
-		wildcardChild := pn.childList[len(pn.childList)-1]
-		pn.childList = append(pn.childList[:len(pn.childList)-1], child, wildcardChild)
+		// keep the wildcard children at the end of the array
+		pn.insertChildAt(len(pn.indices)-1, child)
 	} else {
 		pn.childList = append(pn.childList, child)
 	}
 }
 
+// addWildChild 添加命名参数子节点, 带约束的命名参数排在无约束的之前
+func (pn *PathNode[T]) addWildChild(child *PathNode[T]) {
+	pos := len(pn.childList)
+	if pn.wildChild && child.match != nil {
+		for pos > len(pn.indices) && pn.childList[pos-1].match == nil {
+			pos--
+		}
+	}
+
+	pn.insertChildAt(pos, child)
+	pn.wildChild = true
+}
+
+// insertChildAt 在指定位置插入子节点
+func (pn *PathNode[T]) insertChildAt(pos int, child *PathNode[T]) {
+	pn.childList = append(pn.childList, nil)
+	copy(pn.childList[pos+1:], pn.childList[pos:])
+	pn.childList[pos] = child
+}
+
+// wildChildList 获取通配符子节点列表
+func (pn *PathNode[T]) wildChildList() []*PathNode[T] {
+	if !pn.wildChild {
+		return nil
+	}
+
+	return pn.childList[len(pn.indices):]
+}
+
 func longestCommonPrefix(s1, s2 string) int {
 	i := 0
 	max := math.Min(len(s1), len(s2))
@@ -270,6 +508,7 @@ func longestCommonPrefix(s1, s2 string) int {
 // NodeValue 节点值对象
 type NodeValue[T any] struct {
 	Data     *T
+	Params   *Params
 	Tsr      bool
 	FullPath string
 }
@@ -278,65 +517,191 @@ type skippedNode[T any] struct {
 	path        string
 	node        *PathNode[T]
 	paramsCount int16
+	// wild 回退后尝试的通配符子节点下标, 为0时从第一个通配符子节点开始
+	wild int
 }
 
 // GetValue 获取节点值
-func (pn *PathNode[T]) GetValue(path string, skippedNodes *[]skippedNode[T]) (value NodeValue[T]) {
+// params不为nil时, 会先被清空, 匹配到的通配符参数依次追加到params中并通过value.Params返回
+// skippedNodes为回退用的缓冲, 为nil时在内部分配, 需要复用缓冲时可使用 Router 或 AtomicPathNode
+func (pn *PathNode[T]) GetValue(path string, params *Params, skippedNodes *[]skippedNode[T]) (value NodeValue[T]) {
+	if skippedNodes == nil {
+		skippedNodes = new([]skippedNode[T])
+	}
+
+	cfg := pn.config()
+	sep := cfg.separator
+	var (
+		globalParamsCount int16
+		// 从skippedNode回退时跳过静态子节点, 直接尝试通配符子节点
+		skipStatic bool
+		nextWild   int
+		// 已失败的分支中是否存在增删末尾分隔符后可以匹配的路径
+		tsr bool
+	)
+	if params != nil {
+		*params = (*params)[:0]
+	}
+
+	// rollback 回退到最近一个能继续匹配剩余路径的skippedNode
+	rollback := func() bool {
+		for length := len(*skippedNodes); length > 0; length-- {
+			skipped := (*skippedNodes)[length-1]
+			*skippedNodes = (*skippedNodes)[:length-1]
+			if strings.HasSuffix(skipped.path, path) {
+				path = skipped.path
+				pn = skipped.node
+				if value.Params != nil {
+					*value.Params = (*value.Params)[:skipped.paramsCount]
+				}
+				globalParamsCount = skipped.paramsCount
+				skipStatic = true
+				nextWild = skipped.wild
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// fallback 当前分支匹配失败, 记录能否重定向并回退到下一个候选分支
+	// 没有候选分支时设置value.Tsr并返回false, 真正的匹配优先于重定向
+	fallback := func(redirect bool) bool {
+		tsr = tsr || redirect
+		if rollback() {
+			return true
+		}
+
+		value.Tsr = tsr
+		return false
+	}
+
 walk:
 	for {
 		prefix := pn.path
 		if len(path) > len(prefix) {
 			if path[:len(prefix)] == prefix {
+				// 回退时需要的完整剩余路径, 等价于 prefix + path, 避免拼接分配内存
+				unmatched := path
 				path = path[len(prefix):]
 
 				idxc := path[0]
 				for i, c := range []byte(pn.indices) {
+					if skipStatic {
+						break
+					}
+
 					if c == idxc {
 						if pn.wildChild {
-							index := len(*skippedNodes)
-							*skippedNodes = (*skippedNodes)[:index+1]
-							(*skippedNodes)[index] = skippedNode[T]{
-								path: prefix + path,
-								node: &PathNode[T]{
-									path:      pn.path,
-									wildChild: pn.wildChild,
-									nType:     pn.nType,
-									priority:  pn.priority,
-									childList: pn.childList,
-									fullPath:  pn.fullPath,
-									data:      pn.data,
-								},
-							}
+							*skippedNodes = append(*skippedNodes, skippedNode[T]{
+								path:        unmatched,
+								node:        pn,
+								paramsCount: globalParamsCount,
+							})
 						}
 
 						pn = pn.childList[i]
 						continue walk
 					}
 				}
+				skipStatic = false
 
 				if !pn.wildChild {
-					if path != "/" {
-						for length := len(*skippedNodes); length > 0; length-- {
-							skippedNode := (*skippedNodes)[length-1]
-							*skippedNodes = (*skippedNodes)[:length-1]
-							if strings.HasSuffix(skippedNode.path, path) {
-								path = skippedNode.path
-								pn = skippedNode.node
-								continue walk
-							}
-						}
+					if fallback(path == sep && pn.data != nil) {
+						continue walk
 					}
-
-					value.Tsr = path == "/" && pn.data != nil
 					return
 				}
 
-				pn = pn.childList[len(pn.childList)-1]
+				// Handle wildcard children, which are always at the end of the array
+				i := len(pn.indices)
+				if nextWild > 0 {
+					i, nextWild = nextWild, 0
+				}
+				if i+1 < len(pn.childList) {
+					// 还有其他候选的命名参数, 失败时回退尝试下一个
+					*skippedNodes = append(*skippedNodes, skippedNode[T]{
+						path:        unmatched,
+						node:        pn,
+						paramsCount: globalParamsCount,
+						wild:        i + 1,
+					})
+				}
+				pn = pn.childList[i]
+				globalParamsCount++
 
 				switch pn.nType {
+				case param:
+					// Find param end (either separator or path end)
+					end := strings.Index(path, sep)
+					if end < 0 {
+						end = len(path)
+					}
+
+					if pn.match != nil && !pn.match(path[:end]) {
+						if fallback(false) {
+							continue walk
+						}
+						return
+					}
+
+					// Save param value
+					if params != nil {
+						if value.Params == nil {
+							value.Params = params
+						}
+						*value.Params = append(*value.Params, Param{
+							Key:   paramKey(pn.path),
+							Value: path[:end],
+						})
+					}
+
+					// we need to go deeper!
+					if end < len(path) {
+						if len(pn.childList) > 0 {
+							path = path[end:]
+							pn = pn.childList[0]
+							continue walk
+						}
+
+						// ... but we can't
+						if fallback(len(path) == end+len(sep)) {
+							continue walk
+						}
+						return
+					}
+
+					if value.Data = pn.data; value.Data != nil {
+						value.FullPath = pn.fullPath
+						return
+					}
+					// No data found. Check if data for this path + a
+					// trailing slash exists for TSR recommendation
+					redirect := false
+					if len(pn.childList) == 1 {
+						child := pn.childList[0]
+						redirect = (child.path == sep && child.data != nil) || (child.path == "" && child.indices == sep[:1])
+					}
+					if fallback(redirect) {
+						continue walk
+					}
+					return
+
 				case catchAll:
+					// Save param value
+					if params != nil {
+						if value.Params == nil {
+							value.Params = params
+						}
+						*value.Params = append(*value.Params, Param{
+							Key:   pn.path[strings.IndexByte(pn.path, cfg.catchAll)+1:],
+							Value: path,
+						})
+					}
+
 					value.Data = pn.data
 					value.FullPath = pn.fullPath
+					return
 
 				default:
 					panic("invalid node type")
@@ -345,61 +710,598 @@ walk:
 		}
 
 		if path == prefix {
-			if pn.data == nil && path != "/" {
-				for length := len(*skippedNodes); length > 0; length-- {
-					skippedNode := (*skippedNodes)[length-1]
-					*skippedNodes = (*skippedNodes)[:length-1]
-					if strings.HasSuffix(skippedNode.path, path) {
-						path = skippedNode.path
-						pn = skippedNode.node
-						continue walk
-					}
-				}
-			}
-
 			if value.Data = pn.data; value.Data != nil {
 				value.FullPath = pn.fullPath
 				return
 			}
 
-			if path == "/" && pn.wildChild && pn.nType != root {
-				value.Tsr = true
-				return
+			redirect := path == sep && (pn.nType == static || pn.wildChild && pn.nType != root)
+			if i := strings.IndexByte(pn.indices, sep[0]); !redirect && i >= 0 {
+				child := pn.childList[i]
+				redirect = (child.path == sep && child.data != nil) ||
+					(child.nType == catchAll && child.childList[0].data != nil)
 			}
 
-			if path == "/" && pn.nType == static {
-				value.Tsr = true
-				return
+			if fallback(redirect) {
+				continue walk
 			}
+			return
+		}
 
-			for i, c := range []byte(pn.indices) {
-				if c == '/' {
-					pn = pn.childList[i]
-					value.Tsr = (len(pn.path) == 1 && pn.data != nil) ||
-						(pn.nType == catchAll && pn.childList[0].data != nil)
-					return
+		if fallback(path == sep ||
+			(len(prefix) == len(path)+len(sep) && prefix[len(path):] == sep &&
+				path == prefix[:len(path)] && pn.data != nil)) {
+			continue walk
+		}
+		return
+	}
+}
+
+// Replace 替换路径上已注册的数据, 不改变树结构
+// path 需与注册时的路径完全一致, 路径未注册或t为nil时返回false
+func (pn *PathNode[T]) Replace(path string, t *T) bool {
+	n, _ := pn.findNode(path)
+	if n == nil || n.data == nil || t == nil {
+		return false
+	}
+
+	n.data = t
+	return true
+}
+
+// Remove 移除路径上注册的数据, 并合并因此变得多余的节点
+// path 需与注册时的路径完全一致, 路径未注册时返回false
+func (pn *PathNode[T]) Remove(path string) bool {
+	n, parents := pn.findNode(path)
+	if n == nil || n.data == nil {
+		return false
+	}
+
+	n.data = nil
+
+	// 沿途节点的优先级减一, 并保持静态子节点按优先级排序
+	chain := append(parents, n)
+	chain[0].priority--
+	for i := 1; i < len(chain); i++ {
+		parent, child := chain[i-1], chain[i]
+		if pos := parent.childIndex(child); pos < len(parent.indices) {
+			parent.decrementChildPrio(pos)
+		} else {
+			child.priority--
+		}
+	}
+
+	// 自下而上删除不再有数据和子节点的节点
+	cur := n
+	for depth := len(parents) - 1; depth >= 0 && cur.data == nil && len(cur.childList) == 0; depth-- {
+		parents[depth].removeChild(cur)
+		cur = parents[depth]
+	}
+
+	if cur == pn && cur.data == nil && len(cur.childList) == 0 {
+		*pn = PathNode[T]{cfg: pn.cfg}
+		return true
+	}
+
+	cur.mergeChild()
+	return true
+}
+
+// findNode 按注册时的原始路径查找节点, 同时返回沿途经过的父节点
+func (pn *PathNode[T]) findNode(path string) (*PathNode[T], []*PathNode[T]) {
+	sep := pn.config().separator
+	var parents []*PathNode[T]
+	n := pn
+
+walk:
+	for {
+		if !strings.HasPrefix(path, n.path) {
+			return nil, nil
+		}
+
+		path = path[len(n.path):]
+		if path == "" {
+			return n, parents
+		}
+
+		parents = append(parents, n)
+		c := path[0]
+
+		// separator after param, checked by the prefix of the child
+		if n.nType == param {
+			if len(n.childList) == 1 {
+				n = n.childList[0]
+				continue walk
+			}
+
+			return nil, nil
+		}
+
+		for i := 0; i < len(n.indices); i++ {
+			if c == n.indices[i] {
+				n = n.childList[i]
+				continue walk
+			}
+		}
+
+		for _, wild := range n.wildChildList() {
+			if strings.HasPrefix(path, wild.path) &&
+				(len(path) == len(wild.path) || strings.HasPrefix(path[len(wild.path):], sep)) {
+				n = wild
+				continue walk
+			}
+		}
+
+		return nil, nil
+	}
+}
+
+// childIndex 获取子节点的下标, 不存在时返回-1
+func (pn *PathNode[T]) childIndex(child *PathNode[T]) int {
+	for i, c := range pn.childList {
+		if c == child {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// decrementChildPrio 降低子节点优先级并向后调整位置, 返回新的下标
+func (pn *PathNode[T]) decrementChildPrio(pos int) int {
+	cs := pn.childList
+	cs[pos].priority--
+	prio := cs[pos].priority
+
+	newPos := pos
+	for ; newPos+1 < len(pn.indices) && cs[newPos+1].priority > prio; newPos++ {
+		cs[newPos+1], cs[newPos] = cs[newPos], cs[newPos+1]
+	}
+
+	if newPos != pos {
+		pn.indices = pn.indices[:pos] +
+			pn.indices[pos+1:newPos+1] +
+			pn.indices[pos:pos+1] + pn.indices[newPos+1:]
+	}
+
+	return newPos
+}
+
+// removeChild 删除子节点
+func (pn *PathNode[T]) removeChild(child *PathNode[T]) {
+	i := pn.childIndex(child)
+	if i < 0 {
+		return
+	}
+
+	if i < len(pn.indices) {
+		pn.indices = pn.indices[:i] + pn.indices[i+1:]
+	}
+	pn.childList = append(pn.childList[:i], pn.childList[i+1:]...)
+
+	// wildcard children are always at the end of the array
+	if pn.wildChild && len(pn.childList) == len(pn.indices) {
+		pn.wildChild = false
+	}
+}
+
+// mergeChild 节点没有数据且只剩一个静态子节点时, 将子节点合并到当前节点
+func (pn *PathNode[T]) mergeChild() {
+	if pn.data != nil || pn.wildChild || len(pn.childList) != 1 || len(pn.indices) != 1 ||
+		pn.nType == param || pn.nType == catchAll {
+		return
+	}
+
+	child := pn.childList[0]
+	if child.nType != static {
+		return
+	}
+
+	pn.path += child.path
+	pn.indices = child.indices
+	pn.wildChild = child.wildChild
+	pn.childList = child.childList
+	pn.priority = child.priority
+	pn.fullPath = child.fullPath
+	pn.data = child.data
+}
+
+// Walk 遍历所有已注册的路径, fn返回false时停止遍历
+// 遍历顺序与注册顺序无关: 父路径先于子路径, 同级静态路径按字典序, 通配符路径最后
+func (pn *PathNode[T]) Walk(fn func(fullPath string, data *T) bool) {
+	pn.walk(fn)
+}
+
+// Routes 获取所有已注册的路径, 顺序与 Walk 一致
+func (pn *PathNode[T]) Routes() []string {
+	var result []string
+	pn.Walk(func(fullPath string, _ *T) bool {
+		result = append(result, fullPath)
+		return true
+	})
+
+	return result
+}
+
+func (pn *PathNode[T]) walk(fn func(string, *T) bool) bool {
+	if pn.data != nil && !fn(pn.fullPath, pn.data) {
+		return false
+	}
+
+	// 静态子节点按索引字节排序, 通配符子节点始终在最后
+	order := make([]int, len(pn.childList))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order[:len(pn.indices)], func(i, j int) bool {
+		return pn.indices[order[i]] < pn.indices[order[j]]
+	})
+
+	for _, i := range order {
+		if !pn.childList[i].walk(fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FindCaseInsensitivePath 忽略大小写查找已注册的路径, 可选修正末尾的分隔符
+// 返回修正大小写后的规范路径以及是否找到, 可用于重定向到规范路径
+func (pn *PathNode[T]) FindCaseInsensitivePath(path string, fixTrailingSlash bool) (string, bool) {
+	const stackBufSize = 128
+
+	// 根节点路径的首字节不会经过索引匹配, 需要单独检查
+	if len(pn.path) > 0 && (len(path) == 0 || !strings.EqualFold(path[:1], pn.path[:1])) {
+		return "", false
+	}
+
+	// Use a static sized buffer on the stack in the common case.
+	// If the path is too long, allocate a buffer on the heap instead.
+	buf := make([]byte, 0, stackBufSize)
+	if length := len(path) + 1; length > stackBufSize {
+		buf = make([]byte, 0, length)
+	}
+
+	ciPath := pn.findCaseInsensitivePathRec(
+		pn.config().separator,
+		path,
+		buf,       // Preallocate enough memory for new path
+		[4]byte{}, // Empty rune buffer
+		fixTrailingSlash,
+		false, // Nothing matched yet
+	)
+
+	return string(ciPath), ciPath != nil
+}
+
+// shiftNRuneBytes 将rune字节缓冲左移n个字节
+func shiftNRuneBytes(rb [4]byte, n int) [4]byte {
+	switch n {
+	case 0:
+		return rb
+	case 1:
+		return [4]byte{rb[1], rb[2], rb[3], 0}
+	case 2:
+		return [4]byte{rb[2], rb[3]}
+	case 3:
+		return [4]byte{rb[3]}
+	default:
+		return [4]byte{}
+	}
+}
+
+// hasRuneBytes 节点路径是否与rb中未处理完的rune字节一致
+// 索引只匹配了rune的第一个字节, 多字节rune的其余字节需要在这里检查
+func hasRuneBytes(path string, rb [4]byte) bool {
+	for i := 1; i < len(rb) && i < len(path) && rb[i] != 0; i++ {
+		if path[i] != rb[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// findCaseInsensitivePathRec 递归实现的忽略大小写查找, hasData为ciPath对应的路径是否注册了数据
+func (pn *PathNode[T]) findCaseInsensitivePathRec(sep, path string, ciPath []byte, rb [4]byte, fixTrailingSlash, hasData bool) []byte {
+	npLen := len(pn.path)
+
+walk:
+	for len(path) >= npLen && (npLen == 0 || strings.EqualFold(path[1:npLen], pn.path[1:])) && hasRuneBytes(pn.path, rb) {
+		// Add common prefix to result
+		oldPath := path
+		path = path[npLen:]
+		ciPath = append(ciPath, pn.path...)
+		hasData = pn.data != nil
+
+		if len(path) == 0 {
+			if pn.data != nil {
+				return ciPath
+			}
+
+			// No data found.
+			// Try to fix the path by adding a trailing separator
+			if fixTrailingSlash {
+				for i, c := range []byte(pn.indices) {
+					if c == sep[0] {
+						pn = pn.childList[i]
+						if (pn.path == sep && pn.data != nil) ||
+							(pn.nType == catchAll && pn.childList[0].data != nil) {
+							return append(ciPath, sep...)
+						}
+						return nil
+					}
 				}
 			}
+			return nil
+		}
 
-			return
+		// Try the static children first, the wildcard child may still match
+		// if none of them does
+		if len(pn.indices) > 0 {
+			// Skip rune bytes already processed
+			rb = shiftNRuneBytes(rb, npLen)
+
+			if rb[0] != 0 {
+				// Old rune not finished
+				idxc := rb[0]
+				for i, c := range []byte(pn.indices) {
+					if c == idxc {
+						if !pn.wildChild {
+							// continue with child node
+							pn = pn.childList[i]
+							npLen = len(pn.path)
+							continue walk
+						}
+
+						if out := pn.childList[i].findCaseInsensitivePathRec(
+							sep, path, ciPath, rb, fixTrailingSlash, hasData,
+						); out != nil {
+							return out
+						}
+						break
+					}
+				}
+			} else {
+				// Process a new rune
+				var rv rune
+
+				// Find rune start
+				// Runes are up to 4 byte long,
+				// -4 would definitely be another rune
+				var off int
+				if npLen == 0 {
+					// Nodes with an empty path (e.g. the one between a param
+					// and a catch-all) consume nothing, the rune starts at path
+					rv, _ = utf8.DecodeRuneInString(path)
+				}
+				for max := math.Min(npLen, 3); off < max; off++ {
+					if i := npLen - off; utf8.RuneStart(oldPath[i]) {
+						// read rune from cached path
+						rv, _ = utf8.DecodeRuneInString(oldPath[i:])
+						break
+					}
+				}
+
+				// Calculate lowercase bytes of current rune
+				lo := unicode.ToLower(rv)
+				utf8.EncodeRune(rb[:], lo)
+
+				// Skip already processed bytes
+				rb = shiftNRuneBytes(rb, off)
+
+				idxc := rb[0]
+				for i, c := range []byte(pn.indices) {
+					// Lowercase matches
+					if c == idxc {
+						// must use a recursive approach since both the
+						// uppercase byte and the lowercase byte might exist
+						// as an index
+						if out := pn.childList[i].findCaseInsensitivePathRec(
+							sep, path, ciPath, rb, fixTrailingSlash, hasData,
+						); out != nil {
+							return out
+						}
+						break
+					}
+				}
+
+				// If we found no match, the same for the uppercase rune,
+				// if it differs
+				if up := unicode.ToUpper(rv); up != lo {
+					utf8.EncodeRune(rb[:], up)
+					rb = shiftNRuneBytes(rb, off)
+
+					idxc := rb[0]
+					for i, c := range []byte(pn.indices) {
+						// Uppercase matches
+						if c == idxc {
+							if !pn.wildChild {
+								// Continue with child node
+								pn = pn.childList[i]
+								npLen = len(pn.path)
+								continue walk
+							}
+
+							if out := pn.childList[i].findCaseInsensitivePathRec(
+								sep, path, ciPath, rb, fixTrailingSlash, hasData,
+							); out != nil {
+								return out
+							}
+							break
+						}
+					}
+				}
+			}
 		}
 
-		value.Tsr = path == "/" ||
-			(len(prefix) == len(path)+1 && prefix[len(path)] == '/' &&
-				path == prefix[:len(prefix)-1] && pn.data != nil)
+		if !pn.wildChild {
+			// Nothing found. We can recommend to redirect to the same URL
+			// without a trailing slash if a leaf exists for that path
+			if fixTrailingSlash && path == sep && pn.data != nil {
+				return ciPath
+			}
+			return nil
+		}
 
-		if !value.Tsr && path != "/" {
-			for length := len(*skippedNodes); length > 0; length-- {
-				skippedNode := (*skippedNodes)[length-1]
-				*skippedNodes = (*skippedNodes)[:length-1]
-				if strings.HasSuffix(skippedNode.path, path) {
-					path = skippedNode.path
-					pn = skippedNode.node
-					continue walk
+		// Handle wildcard children, which are always at the end of the array
+		for _, wild := range pn.wildChildList() {
+			switch wild.nType {
+			case param:
+				if out := wild.findCaseInsensitiveParam(sep, path, ciPath, fixTrailingSlash); out != nil {
+					return out
 				}
+
+			case catchAll:
+				return append(ciPath, path...)
+
+			default:
+				panic("invalid node type")
+			}
+		}
+
+		return nil
+	}
+
+	// Nothing found.
+	// Try to fix the path by adding / removing a trailing separator
+	if fixTrailingSlash {
+		if path == sep && hasData {
+			return ciPath
+		}
+		if len(path)+len(sep) == npLen && pn.path[len(path):] == sep &&
+			strings.EqualFold(path[1:], pn.path[1:len(path)]) && hasRuneBytes(pn.path, rb) && pn.data != nil {
+			return append(ciPath, pn.path...)
+		}
+	}
+	return nil
+}
+
+// findCaseInsensitiveParam 忽略大小写查找命名参数节点, 参数值保持原样
+func (pn *PathNode[T]) findCaseInsensitiveParam(sep, path string, ciPath []byte, fixTrailingSlash bool) []byte {
+	// Find param end (either separator or path end)
+	end := strings.Index(path, sep)
+	if end < 0 {
+		end = len(path)
+	}
+
+	if pn.match != nil && !pn.match(path[:end]) {
+		return nil
+	}
+
+	// Add param value to case insensitive path
+	ciPath = append(ciPath, path[:end]...)
+
+	// We need to go deeper!
+	if end < len(path) {
+		if len(pn.childList) > 0 {
+			// Continue with child node
+			return pn.childList[0].findCaseInsensitivePathRec(
+				sep, path[end:], ciPath, [4]byte{}, fixTrailingSlash, pn.data != nil,
+			)
+		}
+
+		// ... but we can't
+		if fixTrailingSlash && len(path) == end+len(sep) && pn.data != nil {
+			return ciPath
+		}
+		return nil
+	}
+
+	if pn.data != nil {
+		return ciPath
+	}
+
+	if fixTrailingSlash && len(pn.childList) == 1 {
+		// No data found. Check if data for this path + a
+		// trailing slash exists
+		child := pn.childList[0]
+		if child.path == sep && child.data != nil {
+			return append(ciPath, sep...)
+		}
+	}
+
+	return nil
+}
+
+// LongestPrefix 最长前缀匹配, 查找完整路径是path前缀的最深的已注册节点
+// 只沿静态节点匹配, 未找到时value.Data为nil
+func (pn *PathNode[T]) LongestPrefix(path string) (value NodeValue[T]) {
+walk:
+	for {
+		if !strings.HasPrefix(path, pn.path) {
+			return
+		}
+
+		path = path[len(pn.path):]
+		if pn.data != nil && pn.nType != param && pn.nType != catchAll {
+			value.Data = pn.data
+			value.FullPath = pn.fullPath
+		}
+
+		if path == "" || pn.nType == param {
+			return
+		}
+
+		c := path[0]
+		for i := 0; i < len(pn.indices); i++ {
+			if c == pn.indices[i] {
+				pn = pn.childList[i]
+				continue walk
 			}
 		}
 
 		return
 	}
 }
+
+// WithPrefix 遍历所有完整路径以prefix开头的已注册路径, fn返回false时停止遍历
+// 遍历顺序与 Walk 一致
+func (pn *PathNode[T]) WithPrefix(prefix string, fn func(fullPath string, data *T) bool) {
+	pn.withPrefix(prefix, fn)
+}
+
+func (pn *PathNode[T]) withPrefix(prefix string, fn func(string, *T) bool) bool {
+walk:
+	for {
+		if len(prefix) <= len(pn.path) {
+			if strings.HasPrefix(pn.path, prefix) {
+				return pn.walk(fn)
+			}
+			return true
+		}
+
+		if !strings.HasPrefix(prefix, pn.path) {
+			return true
+		}
+
+		prefix = prefix[len(pn.path):]
+		c := prefix[0]
+
+		// separator after param, checked by the prefix of the child
+		if pn.nType == param {
+			if len(pn.childList) == 1 {
+				pn = pn.childList[0]
+				continue walk
+			}
+
+			return true
+		}
+
+		for i := 0; i < len(pn.indices); i++ {
+			if c == pn.indices[i] {
+				pn = pn.childList[i]
+				continue walk
+			}
+		}
+
+		for _, wild := range pn.wildChildList() {
+			if !wild.withPrefix(prefix, fn) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
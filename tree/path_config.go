@@ -0,0 +1,108 @@
+package tree
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// pathConfig 路径树配置
+type pathConfig struct {
+	// separator 路径段分隔符
+	separator string
+	// param 命名参数标识
+	param byte
+	// catchAll 全匹配标识
+	catchAll byte
+}
+
+var defaultPathConfig = pathConfig{
+	separator: "/",
+	param:     ':',
+	catchAll:  '*',
+}
+
+// PathOption 路径树配置项
+type PathOption func(*pathConfig)
+
+// WithSeparator 设置路径段分隔符, 默认为"/"
+func WithSeparator(separator string) PathOption {
+	return func(cfg *pathConfig) {
+		cfg.separator = separator
+	}
+}
+
+// WithParamMarker 设置命名参数标识, 默认为':'
+func WithParamMarker(c byte) PathOption {
+	return func(cfg *pathConfig) {
+		cfg.param = c
+	}
+}
+
+// WithCatchAllMarker 设置全匹配标识, 默认为'*'
+func WithCatchAllMarker(c byte) PathOption {
+	return func(cfg *pathConfig) {
+		cfg.catchAll = c
+	}
+}
+
+// NewPathNode 新建路径树的根节点
+// 不传配置项时与直接使用 &PathNode[T]{} 等价, 配置项不合法时返回 ErrInvalidConfig
+func NewPathNode[T any](options ...PathOption) (*PathNode[T], error) {
+	cfg := defaultPathConfig
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &PathNode[T]{
+		cfg: &cfg,
+	}, nil
+}
+
+// validate 检查配置是否合法
+func (cfg *pathConfig) validate() error {
+	if cfg.separator == "" {
+		return fmt.Errorf("%w: separator must not be empty", ErrInvalidConfig)
+	}
+
+	if cfg.param == cfg.catchAll {
+		return fmt.Errorf("%w: param and catch-all markers must differ, both are '%c'",
+			ErrInvalidConfig, cfg.param)
+	}
+
+	if strings.ContainsAny(cfg.separator, string([]byte{constraintStart, constraintEnd})) {
+		return fmt.Errorf("%w: separator '%s' must not contain constraint delimiters", ErrInvalidConfig, cfg.separator)
+	}
+
+	for _, c := range []byte{cfg.param, cfg.catchAll} {
+		if c >= utf8.RuneSelf || c == constraintStart || c == constraintEnd {
+			return fmt.Errorf("%w: wildcard marker %#x must be an ASCII character other than constraint delimiters",
+				ErrInvalidConfig, c)
+		}
+
+		if strings.IndexByte(cfg.separator, c) >= 0 {
+			return fmt.Errorf("%w: separator '%s' must not contain wildcard marker '%c'",
+				ErrInvalidConfig, cfg.separator, c)
+		}
+	}
+
+	return nil
+}
+
+// isWildcard 是否为通配符标识
+func (cfg *pathConfig) isWildcard(c byte) bool {
+	return c == cfg.param || c == cfg.catchAll
+}
+
+// config 获取路径树配置, 只对根节点有效
+func (pn *PathNode[T]) config() *pathConfig {
+	if pn.cfg == nil {
+		return &defaultPathConfig
+	}
+
+	return pn.cfg
+}
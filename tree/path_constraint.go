@@ -0,0 +1,108 @@
+package tree
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+/*
+	带约束的命名参数, 只有参数值满足约束时才会匹配, 否则回退尝试其他候选路径
+	ex:
+	/orders/:id<int>
+	/files/:name<[a-z]+\.txt>
+
+	约束可以是内置类型名, 也可以是正则表达式(会自动添加首尾锚点)
+	同一位置可以注册多个约束不同的命名参数, 以及最多一个无约束的命名参数, 匹配时带约束的参数优先
+*/
+
+const (
+	constraintStart = '<'
+	constraintEnd   = '>'
+)
+
+// paramConstraints 内置的参数约束
+var paramConstraints = map[string]func(string) bool{
+	"int": func(s string) bool {
+		if len(s) > 1 && (s[0] == '-' || s[0] == '+') {
+			s = s[1:]
+		}
+
+		return isDigits(s)
+	},
+	"uint": isDigits,
+	"alpha": func(s string) bool {
+		return len(s) > 0 && strings.IndexFunc(s, func(r rune) bool {
+			return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z')
+		}) < 0
+	},
+	"alnum": func(s string) bool {
+		return len(s) > 0 && strings.IndexFunc(s, func(r rune) bool {
+			return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+		}) < 0
+	},
+	"hex": func(s string) bool {
+		return len(s) > 0 && strings.IndexFunc(s, func(r rune) bool {
+			return !('a' <= r && r <= 'f' || 'A' <= r && r <= 'F' || '0' <= r && r <= '9')
+		}) < 0
+	},
+	"uuid": regexp.MustCompile(
+		`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`,
+	).MatchString,
+}
+
+func isDigits(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// splitParam 拆分命名参数通配符, 返回参数名和约束表达式
+// ex: ":id<int>" -> "id", "int"
+func splitParam(wildcard string) (name, constraint string, ok bool) {
+	start := strings.IndexByte(wildcard, constraintStart)
+	if start < 0 {
+		return wildcard[1:], "", true
+	}
+
+	if wildcard[len(wildcard)-1] != constraintEnd {
+		return wildcard[1:start], "", false
+	}
+
+	return wildcard[1:start], wildcard[start+1 : len(wildcard)-1], true
+}
+
+// paramKey 获取命名参数的参数名
+func paramKey(wildcard string) string {
+	if i := strings.IndexByte(wildcard, constraintStart); i > 0 {
+		return wildcard[1:i]
+	}
+
+	return wildcard[1:]
+}
+
+// compileConstraint 编译参数约束, 约束为空时返回nil
+func compileConstraint(constraint string) (func(string) bool, error) {
+	if constraint == "" {
+		return nil, nil
+	}
+
+	if match, exists := paramConstraints[constraint]; exists {
+		return match, nil
+	}
+
+	re, err := regexp.Compile("^(?:" + constraint + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid constraint '%s': %v", ErrInvalidWildcard, constraint, err)
+	}
+
+	return re.MatchString, nil
+}
@@ -0,0 +1,162 @@
+package tree
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+/*
+	写时复制的并发路径树
+	写操作只复制从根节点到修改位置沿途的节点, 在副本上完成修改后通过原子指针发布新版本
+	读操作无锁, 每次读取都基于某个完整的版本, 不会看到修改了一半的树
+*/
+
+// AtomicPathNode 支持并发读写的路径树
+// 写操作之间互斥, 读操作无锁且可以与写操作并发执行
+type AtomicPathNode[T any] struct {
+	root atomic.Pointer[PathNode[T]]
+	mu   sync.Mutex
+
+	skippedPool sync.Pool
+}
+
+// NewAtomicPathNode 构造函数, 配置项与 NewPathNode 相同
+func NewAtomicPathNode[T any](options ...PathOption) (*AtomicPathNode[T], error) {
+	pn, err := NewPathNode[T](options...)
+	if err != nil {
+		return nil, err
+	}
+
+	apn := &AtomicPathNode[T]{}
+	apn.root.Store(pn)
+	return apn, nil
+}
+
+// Load 获取当前版本的路径树
+// 返回的树可能被其他goroutine同时读取, 只能调用只读方法, 不能修改
+func (apn *AtomicPathNode[T]) Load() *PathNode[T] {
+	if pn := apn.root.Load(); pn != nil {
+		return pn
+	}
+
+	return &PathNode[T]{}
+}
+
+// AddNode 添加节点, 路径不合法或与已有路径冲突时panic
+func (apn *AtomicPathNode[T]) AddNode(path string, t *T) {
+	if err := apn.TryAddNode(path, t); err != nil {
+		panic(err)
+	}
+}
+
+// TryAddNode 添加节点, 失败时不发布新版本, 错误与 PathNode.TryAddNode 相同
+func (apn *AtomicPathNode[T]) TryAddNode(path string, t *T) error {
+	apn.mu.Lock()
+	defer apn.mu.Unlock()
+
+	pn := apn.Load().clonePath(path)
+	if err := pn.TryAddNode(path, t); err != nil {
+		return err
+	}
+
+	apn.root.Store(pn)
+	return nil
+}
+
+// Replace 替换路径上已注册的数据, 规则同 PathNode.Replace
+func (apn *AtomicPathNode[T]) Replace(path string, t *T) bool {
+	apn.mu.Lock()
+	defer apn.mu.Unlock()
+
+	pn := apn.Load().clonePath(path)
+	if !pn.Replace(path, t) {
+		return false
+	}
+
+	apn.root.Store(pn)
+	return true
+}
+
+// Remove 移除路径上注册的数据, 规则同 PathNode.Remove
+func (apn *AtomicPathNode[T]) Remove(path string) bool {
+	apn.mu.Lock()
+	defer apn.mu.Unlock()
+
+	pn := apn.Load().clonePath(path)
+	if !pn.Remove(path) {
+		return false
+	}
+
+	apn.root.Store(pn)
+	return true
+}
+
+// GetValue 在当前版本上获取节点值, params同 PathNode.GetValue, 回退用的缓冲由内部缓冲池管理
+func (apn *AtomicPathNode[T]) GetValue(path string, params *Params) NodeValue[T] {
+	skippedNodes, _ := apn.skippedPool.Get().(*[]skippedNode[T])
+	if skippedNodes == nil {
+		skippedNodes = new([]skippedNode[T])
+	}
+
+	value := apn.Load().GetValue(path, params, skippedNodes)
+
+	*skippedNodes = (*skippedNodes)[:0]
+	apn.skippedPool.Put(skippedNodes)
+	return value
+}
+
+// clone 浅复制节点, 子节点列表复制一份, 子节点本身仍与原节点共享
+func (pn *PathNode[T]) clone() *PathNode[T] {
+	n := *pn
+	n.childList = append([]*PathNode[T](nil), pn.childList...)
+	return &n
+}
+
+// clonePath 复制从根节点开始沿path经过的节点, 返回新的根节点
+// 添加、替换和移除path时只会修改这些节点(及其子节点列表), 其余节点与原树共享
+func (pn *PathNode[T]) clonePath(path string) *PathNode[T] {
+	sep := pn.config().separator
+	newRoot := pn.clone()
+	n := newRoot
+
+walk:
+	for {
+		i := longestCommonPrefix(path, n.path)
+		if i < len(n.path) || i == len(path) {
+			return newRoot
+		}
+
+		path = path[i:]
+		c := path[0]
+
+		// separator after param
+		if n.nType == param && len(n.childList) == 1 {
+			n.childList[0] = n.childList[0].clone()
+			n = n.childList[0]
+			continue walk
+		}
+
+		for i := 0; i < len(n.indices); i++ {
+			if c == n.indices[i] {
+				n.childList[i] = n.childList[i].clone()
+				n = n.childList[i]
+				continue walk
+			}
+		}
+
+		if n.wildChild {
+			for i := len(n.indices); i < len(n.childList); i++ {
+				wild := n.childList[i]
+				if strings.HasPrefix(path, wild.path) &&
+					(len(path) == len(wild.path) || strings.HasPrefix(path[len(wild.path):], sep)) {
+					n.childList[i] = wild.clone()
+					n = n.childList[i]
+					continue walk
+				}
+			}
+		}
+
+		return newRoot
+	}
+}
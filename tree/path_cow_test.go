@@ -0,0 +1,166 @@
+package tree
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fingerprint 记录整棵树的结构, 用于判断树是否被修改
+func fingerprint[T any](pn *PathNode[T]) string {
+	var sb strings.Builder
+	var rec func(n *PathNode[T], depth int)
+	rec = func(n *PathNode[T], depth int) {
+		fmt.Fprintf(&sb, "%d|%s|%s|%d|%v|%d|%p|%s\n",
+			depth, n.path, n.indices, n.priority, n.wildChild, n.nType, n.data, n.fullPath)
+		for _, child := range n.childList {
+			rec(child, depth+1)
+		}
+	}
+	rec(pn, 0)
+
+	return sb.String()
+}
+
+func TestAtomicPathNode(t *testing.T) {
+	routes := []string{
+		"/",
+		"/cmd/:tool/",
+		"/cmd/:tool/:sub",
+		"/cmd/whoami",
+		"/src/*filepath",
+		"/search/",
+		"/search/:query",
+		"/user_:name",
+		"/user_:name/about",
+		"/files/:dir/*filepath",
+		"/doc/",
+		"/doc/go_faq.html",
+		"/doc/go1.html",
+		"/info/:user/public",
+		"/info/:user/project/:project",
+		"/orders/:id<int>",
+		"/orders/:name",
+	}
+
+	apn, err := NewAtomicPathNode[string]()
+	assert.NoError(t, err)
+
+	type snapshot struct {
+		pn          *PathNode[string]
+		fingerprint string
+	}
+	var snapshots []snapshot
+	record := func() {
+		pn := apn.Load()
+		snapshots = append(snapshots, snapshot{pn, fingerprint(pn)})
+	}
+
+	record()
+	for i := range routes {
+		apn.AddNode(routes[i], &routes[i])
+		record()
+	}
+	checkPriorities(t, apn.Load())
+
+	// 失败的写操作不发布新版本
+	pn := apn.Load()
+	assert.Error(t, apn.TryAddNode("/src/:file", nil))
+	assert.False(t, apn.Remove("/doc/go"))
+	assert.False(t, apn.Replace("/doc/go", &routes[0]))
+	assert.Same(t, pn, apn.Load())
+
+	replaced := "replaced"
+	assert.True(t, apn.Replace("/doc/go1.html", &replaced))
+	record()
+	v := apn.GetValue("/doc/go1.html", nil)
+	if assert.NotNil(t, v.Data) {
+		assert.Equal(t, "replaced", *v.Data)
+	}
+	v = pn.GetValue("/doc/go1.html", nil, &[]skippedNode[string]{})
+	if assert.NotNil(t, v.Data) {
+		assert.Equal(t, "/doc/go1.html", *v.Data)
+	}
+
+	for i, route := range routes {
+		if i%2 == 0 {
+			continue
+		}
+
+		assert.True(t, apn.Remove(route), route)
+		record()
+		checkPriorities(t, apn.Load())
+	}
+
+	// 旧版本不受之后写操作的影响
+	for i, s := range snapshots {
+		assert.Equal(t, s.fingerprint, fingerprint(s.pn), i)
+	}
+
+	assert.Len(t, snapshots[len(routes)].pn.Routes(), len(routes))
+	assert.Len(t, apn.Load().Routes(), (len(routes)+1)/2)
+
+	_, err = NewAtomicPathNode[string](WithSeparator(""))
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+
+	var zero AtomicPathNode[string]
+	assert.Nil(t, zero.GetValue("/", nil).Data)
+	zero.AddNode("/a", &routes[0])
+	assert.Equal(t, []string{"/a"}, zero.Load().Routes())
+}
+
+func TestAtomicPathNodeConcurrent(t *testing.T) {
+	apn, err := NewAtomicPathNode[string]()
+	assert.NoError(t, err)
+
+	stable := []string{"/users/:id", "/users/new", "/files/*filepath", "/orders/:id<int>"}
+	for i := range stable {
+		apn.AddNode(stable[i], &stable[i])
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			params := make(Params, 0, 2)
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				v := apn.GetValue("/users/42", &params)
+				if assert.NotNil(t, v.Data) {
+					assert.Equal(t, "42", v.Params.ByName("id"))
+				}
+				v = apn.GetValue("/users/new", &params)
+				if assert.NotNil(t, v.Data) {
+					assert.Equal(t, "/users/new", *v.Data)
+				}
+				v = apn.GetValue("/orders/7", &params)
+				if assert.NotNil(t, v.Data) {
+					assert.Equal(t, "/orders/:id<int>", *v.Data)
+				}
+			}
+		}()
+	}
+
+	paths := make([]string, 200)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("/users/%d/posts/:post", i)
+		apn.AddNode(paths[i], &paths[i])
+		if i%3 == 0 {
+			assert.True(t, apn.Remove(paths[i]))
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	checkPriorities(t, apn.Load())
+}
@@ -0,0 +1,165 @@
+package tree
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+/*
+	输出路径树的内部结构, 便于排查通配符冲突等问题
+	ex:
+	"/" [root priority=3 indices="cs"] => /
+	├── "cmd/" [static priority=1 wildChild]
+	│   └── ":tool" [param priority=1] => /cmd/:tool
+	└── "src" [static priority=1 indices="/"]
+	    └── "" [catchAll priority=1 wildChild]
+	        └── "/*filepath" [catchAll priority=1] => /src/*filepath
+*/
+
+// String 实现fmt.Stringer接口
+func (t nodeType) String() string {
+	switch t {
+	case static:
+		return "static"
+	case root:
+		return "root"
+	case param:
+		return "param"
+	case catchAll:
+		return "catchAll"
+	default:
+		return "nodeType(" + strconv.Itoa(int(t)) + ")"
+	}
+}
+
+// dumpWriter 记录第一次写入错误, 之后的写入直接忽略
+type dumpWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (dw *dumpWriter) printf(format string, args ...any) {
+	if dw.err == nil {
+		_, dw.err = fmt.Fprintf(dw.w, format, args...)
+	}
+}
+
+// Dump 以缩进的文本树形式输出路径树的内部结构
+// 每行依次为节点路径、节点类型、优先级、静态子节点索引、是否有通配符子节点, 以及注册的完整路径
+func (pn *PathNode[T]) Dump(w io.Writer) error {
+	dw := &dumpWriter{w: w}
+	pn.dump(dw, "", "")
+	return dw.err
+}
+
+func (pn *PathNode[T]) dump(dw *dumpWriter, prefix, childPrefix string) {
+	dw.printf("%s%s\n", prefix, pn.label())
+
+	for i, child := range pn.childList {
+		if i == len(pn.childList)-1 {
+			child.dump(dw, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			child.dump(dw, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+// label 节点的单行描述
+func (pn *PathNode[T]) label() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%q [%s priority=%d", pn.path, pn.nType, pn.priority)
+	if pn.indices != "" {
+		fmt.Fprintf(&sb, " indices=%q", pn.indices)
+	}
+	if pn.wildChild {
+		sb.WriteString(" wildChild")
+	}
+	sb.WriteByte(']')
+	if pn.data != nil {
+		sb.WriteString(" => ")
+		sb.WriteString(pn.fullPath)
+	}
+
+	return sb.String()
+}
+
+// DOT 以Graphviz的DOT格式输出路径树的内部结构
+// 静态子节点的边以索引字节标注, 通配符子节点的边为虚线, 注册了数据的节点加粗显示
+func (pn *PathNode[T]) DOT(w io.Writer) error {
+	dw := &dumpWriter{w: w}
+	dw.printf("digraph PathNode {\n")
+	dw.printf("\tnode [shape=box, fontname=monospace];\n")
+	id := 0
+	pn.dot(dw, &id)
+	dw.printf("}\n")
+
+	return dw.err
+}
+
+func (pn *PathNode[T]) dot(dw *dumpWriter, id *int) int {
+	self := *id
+	*id++
+
+	style := ""
+	if pn.data != nil {
+		style = ", style=bold"
+	}
+	dw.printf("\tn%d [label=%s%s];\n", self, dotQuote(pn.label()), style)
+
+	for i, child := range pn.childList {
+		c := child.dot(dw, id)
+		if i < len(pn.indices) {
+			dw.printf("\tn%d -> n%d [label=%s];\n", self, c, dotQuote(pn.indices[i:i+1]))
+		} else {
+			dw.printf("\tn%d -> n%d [style=dashed];\n", self, c)
+		}
+	}
+
+	return self
+}
+
+// dotQuote 转义为DOT格式的字符串
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// pathNodeJSON 路径节点的JSON结构
+type pathNodeJSON struct {
+	Path      string          `json:"path"`
+	Type      string          `json:"type"`
+	Priority  uint32          `json:"priority"`
+	Indices   string          `json:"indices,omitempty"`
+	WildChild bool            `json:"wildChild,omitempty"`
+	FullPath  string          `json:"fullPath,omitempty"`
+	HasData   bool            `json:"hasData,omitempty"`
+	Children  []*pathNodeJSON `json:"children,omitempty"`
+}
+
+// MarshalJSON 实现json.Marshaler接口, 输出路径树的内部结构, 不包含注册的数据
+// fullPath 只在注册了数据的节点上输出
+func (pn *PathNode[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(pn.toJSON())
+}
+
+func (pn *PathNode[T]) toJSON() *pathNodeJSON {
+	node := &pathNodeJSON{
+		Path:      pn.path,
+		Type:      pn.nType.String(),
+		Priority:  pn.priority,
+		Indices:   pn.indices,
+		WildChild: pn.wildChild,
+		HasData:   pn.data != nil,
+	}
+	if pn.data != nil {
+		node.FullPath = pn.fullPath
+	}
+
+	for _, child := range pn.childList {
+		node.Children = append(node.Children, child.toJSON())
+	}
+
+	return node
+}
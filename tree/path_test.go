@@ -1,8 +1,15 @@
 package tree
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestPath(t *testing.T) {
@@ -34,8 +41,788 @@ func TestPath(t *testing.T) {
 		fmt.Println("a2", a)
 	}})
 
-	v := pn.GetValue("/a1", &[]skippedNode[data]{})
+	v := pn.GetValue("/a1", nil, &[]skippedNode[data]{})
 	if v.Data != nil {
 		v.Data.fn("test")
 	}
 }
+
+func TestPathParams(t *testing.T) {
+	pn := &PathNode[string]{}
+	routes := []string{
+		"/users/:id",
+		"/users/:id/posts/:pid",
+		"/users/new",
+		"/src/*filepath",
+		"/info/:user/public",
+		"/info/:user/project/:project",
+	}
+	for i := range routes {
+		pn.AddNode(routes[i], &routes[i])
+	}
+
+	tests := []struct {
+		path     string
+		fullPath string
+		params   Params
+	}{
+		{"/users/42", "/users/:id", Params{{"id", "42"}}},
+		{"/users/new", "/users/new", Params{}},
+		{"/users/newer", "/users/:id", Params{{"id", "newer"}}},
+		{"/users/42/posts/7", "/users/:id/posts/:pid", Params{{"id", "42"}, {"pid", "7"}}},
+		{"/src/", "/src/*filepath", Params{{"filepath", "/"}}},
+		{"/src/some/file.png", "/src/*filepath", Params{{"filepath", "/some/file.png"}}},
+		{"/info/gordon/public", "/info/:user/public", Params{{"user", "gordon"}}},
+		{"/info/gordon/project/go", "/info/:user/project/:project", Params{{"user", "gordon"}, {"project", "go"}}},
+	}
+
+	params := make(Params, 0, 2)
+	for _, tt := range tests {
+		v := pn.GetValue(tt.path, &params, &[]skippedNode[string]{})
+		if assert.NotNil(t, v.Data, tt.path) {
+			assert.Equal(t, tt.fullPath, *v.Data, tt.path)
+		}
+		assert.Equal(t, tt.fullPath, v.FullPath, tt.path)
+		assert.Equal(t, tt.params, params, tt.path)
+	}
+
+	params = make(Params, 0, 2)
+	v := pn.GetValue("/users/42/posts/7", &params, &[]skippedNode[string]{})
+	assert.Equal(t, "42", v.Params.ByName("id"))
+	assert.Equal(t, "7", v.Params.ByName("pid"))
+	assert.Equal(t, "", v.Params.ByName("none"))
+
+	v = pn.GetValue("/users/42/", &params, &[]skippedNode[string]{})
+	assert.Nil(t, v.Data)
+	assert.True(t, v.Tsr)
+
+	assert.Panics(t, func() { pn.AddNode("/users/:name", nil) })
+	assert.Panics(t, func() { pn.AddNode("/src/:file", nil) })
+	assert.Panics(t, func() { pn.AddNode("/bad/:", nil) })
+}
+
+func TestPathNilSkippedNodes(t *testing.T) {
+	pn := &PathNode[string]{}
+	routes := []string{"/users/:id", "/users/new"}
+	for i := range routes {
+		pn.AddNode(routes[i], &routes[i])
+	}
+
+	// 需要回退的查找在skippedNodes为nil时也能正常完成
+	params := make(Params, 0, 1)
+	v := pn.GetValue("/users/newx", &params, nil)
+	if assert.NotNil(t, v.Data) {
+		assert.Equal(t, "/users/:id", *v.Data)
+		assert.Equal(t, "newx", params.ByName("id"))
+	}
+	v = pn.GetValue("/users/new", nil, nil)
+	if assert.NotNil(t, v.Data) {
+		assert.Equal(t, "/users/new", *v.Data)
+	}
+}
+
+func TestPathTryAddNode(t *testing.T) {
+	pn := &PathNode[string]{}
+	routes := []string{"/users/:id", "/src/*filepath", "/static/", "/static/css"}
+	for i := range routes {
+		assert.NoError(t, pn.TryAddNode(routes[i], &routes[i]))
+	}
+	priority := pn.priority
+
+	var conflict *ConflictError
+	err := pn.TryAddNode("/users/:name", nil)
+	if assert.ErrorAs(t, err, &conflict) {
+		assert.True(t, conflict.Wildcard)
+		assert.Equal(t, ":name", conflict.Segment)
+		assert.Equal(t, ":id", conflict.Existing)
+		assert.Equal(t, "/users/:id", conflict.Prefix)
+	}
+
+	err = pn.TryAddNode("/static/*filepath", nil)
+	if assert.ErrorAs(t, err, &conflict) {
+		assert.False(t, conflict.Wildcard)
+		assert.Equal(t, "css", conflict.Existing)
+		assert.Equal(t, "/static/css", conflict.Prefix)
+	}
+
+	assert.ErrorIs(t, pn.TryAddNode("/users/:id", nil), ErrDuplicatePath)
+	assert.ErrorIs(t, pn.TryAddNode("/a/:", nil), ErrInvalidWildcard)
+	assert.ErrorIs(t, pn.TryAddNode("/a/:b:c", nil), ErrInvalidWildcard)
+	assert.ErrorIs(t, pn.TryAddNode("/a/*b/c", nil), ErrInvalidWildcard)
+	assert.ErrorIs(t, pn.TryAddNode("/a*b", nil), ErrInvalidWildcard)
+	assert.Equal(t, priority, pn.priority)
+
+	assert.PanicsWithError(t, (&ConflictError{
+		Segment:  ":name",
+		Path:     "/users/:name",
+		Existing: ":id",
+		Prefix:   "/users/:id",
+		Wildcard: true,
+	}).Error(), func() { pn.AddNode("/users/:name", nil) })
+
+	v := pn.GetValue("/users/42", nil, &[]skippedNode[string]{})
+	if assert.NotNil(t, v.Data) {
+		assert.Equal(t, "/users/:id", *v.Data)
+	}
+}
+
+func checkPriorities[T any](t *testing.T, n *PathNode[T]) uint32 {
+	var prio uint32
+	for _, child := range n.childList {
+		prio += checkPriorities(t, child)
+	}
+
+	if n.data != nil {
+		prio++
+	}
+	assert.Equal(t, prio, n.priority, n.fullPath)
+
+	return prio
+}
+
+func TestPathRemove(t *testing.T) {
+	routes := []string{
+		"/",
+		"/cmd/:tool/",
+		"/cmd/:tool/:sub",
+		"/cmd/whoami",
+		"/src/*filepath",
+		"/search/",
+		"/search/:query",
+		"/user_:name",
+		"/user_:name/about",
+		"/files/:dir/*filepath",
+		"/doc/",
+		"/doc/go_faq.html",
+		"/doc/go1.html",
+		"/info/:user/public",
+		"/info/:user/project/:project",
+	}
+
+	pn := &PathNode[string]{}
+	for i := range routes {
+		pn.AddNode(routes[i], &routes[i])
+	}
+	checkPriorities(t, pn)
+
+	assert.False(t, pn.Remove("/cmd/:name/"))
+	assert.False(t, pn.Remove("/doc/go"))
+	assert.False(t, pn.Replace("/doc/go", &routes[0]))
+
+	replaced := "replaced"
+	assert.True(t, pn.Replace("/doc/go1.html", &replaced))
+	v := pn.GetValue("/doc/go1.html", nil, &[]skippedNode[string]{})
+	if assert.NotNil(t, v.Data) {
+		assert.Equal(t, "replaced", *v.Data)
+	}
+
+	removed := map[string]bool{}
+	for i, route := range routes {
+		if i%2 == 0 {
+			continue
+		}
+
+		assert.True(t, pn.Remove(route), route)
+		assert.False(t, pn.Remove(route), route)
+		removed[route] = true
+		checkPriorities(t, pn)
+	}
+
+	lookups := map[string]string{
+		"/":                            "/",
+		"/cmd/test/3":                  "/cmd/:tool/:sub",
+		"/src/some/file.png":           "/src/*filepath",
+		"/search/someth!ng+in+ünìcodé": "/search/:query",
+		"/user_gopher/about":           "/user_:name/about",
+		"/doc/":                        "/doc/",
+		"/doc/go1.html":                "/doc/go1.html",
+		"/info/gordon/project/go":      "/info/:user/project/:project",
+	}
+	for path, fullPath := range lookups {
+		v := pn.GetValue(path, nil, &[]skippedNode[string]{})
+		assert.Equal(t, fullPath, v.FullPath, path)
+	}
+
+	for _, path := range []string{"/cmd/test/", "/cmd/whoami", "/search/", "/user_gopher", "/doc/go_faq.html", "/info/gordon/public"} {
+		v := pn.GetValue(path, nil, &[]skippedNode[string]{})
+		assert.Nil(t, v.Data, path)
+	}
+
+	for route := range removed {
+		r := route
+		assert.NoError(t, pn.TryAddNode(route, &r), route)
+	}
+	checkPriorities(t, pn)
+
+	for i := len(routes) - 1; i >= 0; i-- {
+		assert.True(t, pn.Remove(routes[i]), routes[i])
+		checkPriorities(t, pn)
+	}
+	assert.Equal(t, PathNode[string]{}, *pn)
+
+	pn.AddNode("/a", &routes[0])
+	v = pn.GetValue("/a", nil, &[]skippedNode[string]{})
+	assert.Equal(t, "/a", v.FullPath)
+}
+
+func TestPathWalk(t *testing.T) {
+	routes := []string{
+		"/users/:id",
+		"/users/new",
+		"/src/*filepath",
+		"/",
+		"/users/:id/posts",
+		"/about",
+		"/users",
+	}
+	expected := []string{
+		"/",
+		"/about",
+		"/src/*filepath",
+		"/users",
+		"/users/new",
+		"/users/:id",
+		"/users/:id/posts",
+	}
+
+	pn := &PathNode[string]{}
+	for i := range routes {
+		pn.AddNode(routes[i], &routes[i])
+	}
+	assert.Equal(t, expected, pn.Routes())
+
+	reversed := &PathNode[string]{}
+	for i := len(routes) - 1; i >= 0; i-- {
+		reversed.AddNode(routes[i], &routes[i])
+	}
+	assert.Equal(t, expected, reversed.Routes())
+
+	var visited []string
+	pn.Walk(func(fullPath string, data *string) bool {
+		assert.Equal(t, fullPath, *data)
+		visited = append(visited, fullPath)
+		return len(visited) < 3
+	})
+	assert.Equal(t, expected[:3], visited)
+
+	assert.Empty(t, (&PathNode[string]{}).Routes())
+}
+
+func TestPathFindCaseInsensitivePath(t *testing.T) {
+	routes := []string{
+		"/hi",
+		"/b/",
+		"/ABC/",
+		"/search/:query",
+		"/cmd/:tool/",
+		"/src/*filepath",
+		"/x",
+		"/x/y",
+		"/y/",
+		"/y/z",
+		"/0/:id",
+		"/0/:id/1",
+		"/1/:id/",
+		"/1/:id/2",
+		"/aa",
+		"/a/",
+		"/doc",
+		"/doc/go_faq.html",
+		"/doc/go1.html",
+		"/users/new",
+		"/users/:id",
+		"/ä/é",
+		"/repos/:owner/*path",
+	}
+
+	pn := &PathNode[string]{}
+	for i := range routes {
+		pn.AddNode(routes[i], &routes[i])
+	}
+
+	// Check out == in for all registered routes
+	for _, route := range routes {
+		out, found := pn.FindCaseInsensitivePath(route, true)
+		assert.True(t, found, route)
+		assert.Equal(t, route, out, route)
+
+		out, found = pn.FindCaseInsensitivePath(route, false)
+		assert.True(t, found, route)
+		assert.Equal(t, route, out, route)
+	}
+
+	tests := []struct {
+		in    string
+		out   string
+		found bool
+		slash bool
+	}{
+		{"/HI", "/hi", true, false},
+		{"/HI/", "/hi", true, true},
+		{"/B", "/b/", true, true},
+		{"/B/", "/b/", true, false},
+		{"/abc", "/ABC/", true, true},
+		{"/abc/", "/ABC/", true, false},
+		{"/aBc", "/ABC/", true, true},
+		{"/SEARCH/QUERY", "/search/QUERY", true, false},
+		{"/CMD/TOOL/", "/cmd/TOOL/", true, false},
+		{"/CMD/TOOL", "/cmd/TOOL/", true, true},
+		{"/SRC/FILE/PATH", "/src/FILE/PATH", true, false},
+		{"/x/Y", "/x/y", true, false},
+		{"/X/y", "/x/y", true, false},
+		{"/Y/", "/y/", true, false},
+		{"/Y", "/y/", true, true},
+		{"/Y/z", "/y/z", true, false},
+		{"/0/ID/1", "/0/ID/1", true, false},
+		{"/1/ID/2", "/1/ID/2", true, false},
+		{"/DOC/GO1.HTML", "/doc/go1.html", true, false},
+		{"/USERS/NEW", "/users/new", true, false},
+		{"/USERS/42", "/users/42", true, false},
+		{"/Ä/É", "/ä/é", true, false},
+		{"/REPOS/x/a/b", "/repos/x/a/b", true, false},
+		{"/Repos/Ä/Ö/", "/repos/Ä/Ö/", true, false},
+		{"/unknown", "", false, true},
+		{"unknown", "", false, true},
+	}
+
+	for _, tt := range tests {
+		out, found := pn.FindCaseInsensitivePath(tt.in, true)
+		assert.Equal(t, tt.found, found, tt.in)
+		assert.Equal(t, tt.out, out, tt.in)
+
+		out, found = pn.FindCaseInsensitivePath(tt.in, false)
+		if tt.slash {
+			assert.False(t, found, tt.in)
+			assert.Empty(t, out, tt.in)
+		} else {
+			assert.Equal(t, tt.found, found, tt.in)
+			assert.Equal(t, tt.out, out, tt.in)
+		}
+	}
+
+	// 多字节rune只有第一个字节相同时不能匹配
+	runes := &PathNode[string]{}
+	for _, route := range []string{"/Ö", "/b"} {
+		route := route
+		runes.AddNode(route, &route)
+	}
+	for _, in := range []string{"/ä", "/ä/", "/é"} {
+		_, found := runes.FindCaseInsensitivePath(in, true)
+		assert.False(t, found, in)
+	}
+	out, found := runes.FindCaseInsensitivePath("/ö/", true)
+	assert.True(t, found)
+	assert.Equal(t, "/Ö", out)
+
+	// 去掉末尾分隔符后的路径没有数据时不能推荐
+	params := &PathNode[string]{}
+	route := "/:p/x"
+	params.AddNode(route, &route)
+	for _, in := range []string{"/q/", "/Q/x/y/", "/q/x/y"} {
+		_, found = params.FindCaseInsensitivePath(in, true)
+		assert.False(t, found, in)
+	}
+	out, found = params.FindCaseInsensitivePath("/Q/X/", true)
+	assert.True(t, found)
+	assert.Equal(t, "/Q/x", out)
+}
+
+func TestPathPrefix(t *testing.T) {
+	routes := []string{
+		"metrics",
+		"metrics.http",
+		"metrics.http.requests",
+		"metrics.grpc",
+		"metrics.:kind.errors",
+		"topics/*rest",
+	}
+
+	pn := &PathNode[string]{}
+	for i := range routes {
+		pn.AddNode(routes[i], &routes[i])
+	}
+
+	tests := []struct {
+		path     string
+		fullPath string
+	}{
+		{"metrics.http.requests.total", "metrics.http.requests"},
+		{"metrics.http.latency", "metrics.http"},
+		{"metrics.grpc", "metrics.grpc"},
+		{"metrics.db", "metrics"},
+		{"metrics", "metrics"},
+		{"metric", ""},
+		{"topics/a/b", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		v := pn.LongestPrefix(tt.path)
+		assert.Equal(t, tt.fullPath, v.FullPath, tt.path)
+		if tt.fullPath == "" {
+			assert.Nil(t, v.Data, tt.path)
+		} else if assert.NotNil(t, v.Data, tt.path) {
+			assert.Equal(t, tt.fullPath, *v.Data, tt.path)
+		}
+	}
+
+	prefixes := map[string][]string{
+		"":                {"metrics", "metrics.grpc", "metrics.http", "metrics.http.requests", "metrics.:kind.errors", "topics/*rest"},
+		"metrics.":        {"metrics.grpc", "metrics.http", "metrics.http.requests", "metrics.:kind.errors"},
+		"metrics.http.":   {"metrics.http.requests"},
+		"metrics.:k":      {"metrics.:kind.errors"},
+		"metrics.:kind.e": {"metrics.:kind.errors"},
+		"topics/":         {"topics/*rest"},
+		"topics/*":        {"topics/*rest"},
+		"metrics.db":      nil,
+		"topics/a":        nil,
+	}
+	for prefix, expected := range prefixes {
+		var result []string
+		pn.WithPrefix(prefix, func(fullPath string, data *string) bool {
+			result = append(result, fullPath)
+			return true
+		})
+		assert.Equal(t, expected, result, prefix)
+	}
+
+	var result []string
+	pn.WithPrefix("metrics.", func(fullPath string, data *string) bool {
+		result = append(result, fullPath)
+		return len(result) < 2
+	})
+	assert.Equal(t, []string{"metrics.grpc", "metrics.http"}, result)
+}
+
+func TestPathConfig(t *testing.T) {
+	_, err := NewPathNode[string](WithSeparator(""))
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	_, err = NewPathNode[string](WithSeparator("::"))
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	_, err = NewPathNode[string](WithParamMarker('*'))
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	_, err = NewPathNode[string](WithCatchAllMarker(0xff))
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+
+	dotted, err := NewPathNode[string](WithSeparator("."))
+	if !assert.NoError(t, err) {
+		return
+	}
+	routes := []string{"metrics.http.*rest", "metrics.:kind.count", "metrics.grpc.count", "metrics.db."}
+	for i := range routes {
+		assert.NoError(t, dotted.TryAddNode(routes[i], &routes[i]), routes[i])
+	}
+	assert.ErrorIs(t, dotted.TryAddNode("metrics/*rest", nil), ErrInvalidWildcard)
+
+	params := make(Params, 0, 1)
+	tests := []struct {
+		path, fullPath, key, value string
+	}{
+		{"metrics.http.requests.total", "metrics.http.*rest", "rest", ".requests.total"},
+		{"metrics.db.count", "metrics.:kind.count", "kind", "db"},
+		{"metrics.grpc.count", "metrics.grpc.count", "", ""},
+		{"metrics.a/b.count", "metrics.:kind.count", "kind", "a/b"},
+	}
+	for _, tt := range tests {
+		v := dotted.GetValue(tt.path, &params, &[]skippedNode[string]{})
+		assert.Equal(t, tt.fullPath, v.FullPath, tt.path)
+		if tt.key != "" && assert.NotNil(t, v.Params, tt.path) {
+			assert.Equal(t, tt.value, v.Params.ByName(tt.key), tt.path)
+		}
+	}
+	assert.True(t, dotted.GetValue("metrics.db", nil, &[]skippedNode[string]{}).Tsr)
+	assert.True(t, dotted.GetValue("metrics.x.count.", nil, &[]skippedNode[string]{}).Tsr)
+
+	out, found := dotted.FindCaseInsensitivePath("METRICS.DB", true)
+	assert.True(t, found)
+	assert.Equal(t, "metrics.db.", out)
+
+	namespaced, err := NewPathNode[string](WithSeparator("::"), WithParamMarker('$'), WithCatchAllMarker('%'))
+	if !assert.NoError(t, err) {
+		return
+	}
+	routes = []string{"app::$module::config", "apps::%rest", "a:x", "a::%rest"}
+	for i := range routes {
+		assert.NoError(t, namespaced.TryAddNode(routes[i], &routes[i]), routes[i])
+	}
+	var conflict *ConflictError
+	assert.ErrorAs(t, namespaced.TryAddNode("app::$name", nil), &conflict)
+	assert.ErrorAs(t, namespaced.TryAddNode("app::%rest", nil), &conflict)
+
+	tests = []struct {
+		path, fullPath, key, value string
+	}{
+		{"app::user::config", "app::$module::config", "module", "user"},
+		{"app::user::other", "", "", ""},
+		{"a:x", "a:x", "", ""},
+		{"a::b::c", "a::%rest", "rest", ":b::c"},
+	}
+	for _, tt := range tests {
+		v := namespaced.GetValue(tt.path, &params, &[]skippedNode[string]{})
+		assert.Equal(t, tt.fullPath, v.FullPath, tt.path)
+		if tt.key != "" && assert.NotNil(t, v.Params, tt.path) {
+			assert.Equal(t, tt.value, v.Params.ByName(tt.key), tt.path)
+		}
+	}
+
+	assert.Equal(t, []string{"a::%rest", "a:x", "app::$module::config", "apps::%rest"}, namespaced.Routes())
+	assert.True(t, namespaced.Remove("app::$module::config"))
+	assert.Equal(t, []string{"a::%rest", "a:x", "apps::%rest"}, namespaced.Routes())
+	for i := range routes {
+		namespaced.Remove(routes[i])
+	}
+	assert.NotNil(t, namespaced.cfg)
+}
+
+func TestPathConstraint(t *testing.T) {
+	routes := []string{
+		"/orders/:id<int>",
+		"/orders/:name",
+		"/orders/new",
+		"/orders/:uuid<uuid>",
+		"/files/:name<[a-z]+\\.txt>",
+		"/files/:name<[0-9]+\\.png>/raw",
+		"/v/:id<int>/a",
+		"/v/:slug/b",
+		"/colors/:hex<hex>",
+	}
+
+	pn := &PathNode[string]{}
+	for i := range routes {
+		assert.NoError(t, pn.TryAddNode(routes[i], &routes[i]), routes[i])
+	}
+	checkPriorities(t, pn)
+
+	tests := []struct {
+		path, fullPath, key, value string
+	}{
+		{"/orders/42", "/orders/:id<int>", "id", "42"},
+		{"/orders/-42", "/orders/:id<int>", "id", "-42"},
+		{"/orders/new", "/orders/new", "", ""},
+		{"/orders/abc", "/orders/:name", "name", "abc"},
+		{"/orders/6ba7b810-9dad-11d1-80b4-00c04fd430c8", "/orders/:uuid<uuid>", "uuid", "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{"/files/readme.txt", "/files/:name<[a-z]+\\.txt>", "name", "readme.txt"},
+		{"/files/01.png/raw", "/files/:name<[0-9]+\\.png>/raw", "name", "01.png"},
+		{"/files/README.txt", "", "", ""},
+		{"/files/01.png", "", "", ""},
+		{"/v/12/a", "/v/:id<int>/a", "id", "12"},
+		{"/v/12/b", "/v/:slug/b", "slug", "12"},
+		{"/v/x/b", "/v/:slug/b", "slug", "x"},
+		{"/v/x/a", "", "", ""},
+		{"/colors/ff00AA", "/colors/:hex<hex>", "hex", "ff00AA"},
+		{"/colors/red", "", "", ""},
+	}
+
+	params := make(Params, 0, 1)
+	for _, tt := range tests {
+		v := pn.GetValue(tt.path, &params, &[]skippedNode[string]{})
+		assert.Equal(t, tt.fullPath, v.FullPath, tt.path)
+		if tt.fullPath == "" {
+			assert.Nil(t, v.Data, tt.path)
+			continue
+		}
+
+		if assert.NotNil(t, v.Data, tt.path) {
+			assert.Equal(t, tt.fullPath, *v.Data, tt.path)
+		}
+		if tt.key != "" && assert.NotNil(t, v.Params, tt.path) {
+			assert.Equal(t, Params{{tt.key, tt.value}}, *v.Params, tt.path)
+		}
+	}
+
+	var conflict *ConflictError
+	assert.ErrorAs(t, pn.TryAddNode("/orders/:other", nil), &conflict)
+	assert.ErrorAs(t, pn.TryAddNode("/orders/:num<int>", nil), &conflict)
+	assert.ErrorAs(t, pn.TryAddNode("/orders/*rest", nil), &conflict)
+	assert.ErrorIs(t, pn.TryAddNode("/orders/:id<int>", nil), ErrDuplicatePath)
+	for _, path := range []string{"/a/:id<>", "/a/:id<[a-z>", "/a/*rest<int>", "/a/:<int>", "/a/:id<int>x", "/a/:id<int", "/w4/:a<a/b>"} {
+		assert.ErrorIs(t, pn.TryAddNode(path, nil), ErrInvalidWildcard, path)
+	}
+	checkPriorities(t, pn)
+
+	out, found := pn.FindCaseInsensitivePath("/ORDERS/42", false)
+	assert.True(t, found)
+	assert.Equal(t, "/orders/42", out)
+	out, found = pn.FindCaseInsensitivePath("/V/12/B", false)
+	assert.True(t, found)
+	assert.Equal(t, "/v/12/b", out)
+
+	assert.Equal(t, []string{
+		"/colors/:hex<hex>",
+		"/files/:name<[a-z]+\\.txt>",
+		"/files/:name<[0-9]+\\.png>/raw",
+		"/orders/new",
+		"/orders/:id<int>",
+		"/orders/:uuid<uuid>",
+		"/orders/:name",
+		"/v/:id<int>/a",
+		"/v/:slug/b",
+	}, pn.Routes())
+
+	var prefixed []string
+	pn.WithPrefix("/orders/:", func(fullPath string, _ *string) bool {
+		prefixed = append(prefixed, fullPath)
+		return true
+	})
+	assert.Equal(t, []string{"/orders/:id<int>", "/orders/:uuid<uuid>", "/orders/:name"}, prefixed)
+
+	assert.True(t, pn.Remove("/orders/:id<int>"))
+	assert.True(t, pn.Remove("/v/:slug/b"))
+	checkPriorities(t, pn)
+	v := pn.GetValue("/orders/42", &params, &[]skippedNode[string]{})
+	assert.Equal(t, "/orders/:name", v.FullPath)
+	v = pn.GetValue("/v/12/b", &params, &[]skippedNode[string]{})
+	assert.Nil(t, v.Data)
+
+	_, err := NewPathNode[string](WithParamMarker('<'))
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestPathBacktrackBeforeRedirect(t *testing.T) {
+	pn := &PathNode[string]{}
+	routes := []string{"/:p/*rest", "/a/:p/ab"}
+	for i := range routes {
+		pn.AddNode(routes[i], &routes[i])
+	}
+
+	// 其他候选分支能够匹配时不推荐重定向
+	params := make(Params, 0, 2)
+	v := pn.GetValue("/a/b/", &params, nil)
+	if assert.NotNil(t, v.Data) {
+		assert.Equal(t, "/:p/*rest", *v.Data)
+		assert.Equal(t, Params{{"p", "a"}, {"rest", "/b/"}}, params)
+	}
+	assert.False(t, v.Tsr)
+
+	// 所有候选分支都失败时保留之前分支的重定向建议
+	pn = &PathNode[string]{}
+	routes = []string{"/b/:p/", "/:p/x/y"}
+	for i := range routes {
+		pn.AddNode(routes[i], &routes[i])
+	}
+	v = pn.GetValue("/b/c", nil, nil)
+	assert.Nil(t, v.Data)
+	assert.True(t, v.Tsr)
+	v = pn.GetValue("/b/x/y", nil, nil)
+	if assert.NotNil(t, v.Data) {
+		assert.Equal(t, "/:p/x/y", *v.Data)
+	}
+}
+
+func TestPathDump(t *testing.T) {
+	routes := []string{"/", "/cmd/:tool", "/src/*filepath"}
+	pn := &PathNode[string]{}
+	for i := range routes {
+		pn.AddNode(routes[i], &routes[i])
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, pn.Dump(&buf))
+	assert.Equal(t, `"/" [root priority=3 indices="cs"] => /
+├── "cmd/" [static priority=1 wildChild]
+│   └── ":tool" [param priority=1] => /cmd/:tool
+└── "src" [static priority=1 indices="/"]
+    └── "" [catchAll priority=1 wildChild]
+        └── "/*filepath" [catchAll priority=1] => /src/*filepath
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(t, pn.DOT(&buf))
+	dot := buf.String()
+	assert.True(t, strings.HasPrefix(dot, "digraph PathNode {\n"))
+	assert.True(t, strings.HasSuffix(dot, "}\n"))
+	assert.Contains(t, dot, `n0 [label="\"/\" [root priority=3 indices=\"cs\"] => /", style=bold];`)
+	assert.Contains(t, dot, `n0 -> n1 [label="c"];`)
+	assert.Contains(t, dot, `n1 -> n2 [style=dashed];`)
+	assert.Equal(t, 5, strings.Count(dot, " -> "))
+
+	var node struct {
+		Path     string `json:"path"`
+		Type     string `json:"type"`
+		Priority uint32 `json:"priority"`
+		Children []struct {
+			Path      string `json:"path"`
+			WildChild bool   `json:"wildChild"`
+			HasData   bool   `json:"hasData"`
+		} `json:"children"`
+	}
+	b, err := json.Marshal(pn)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(b, &node))
+	assert.Equal(t, "/", node.Path)
+	assert.Equal(t, "root", node.Type)
+	assert.Equal(t, uint32(3), node.Priority)
+	if assert.Len(t, node.Children, 2) {
+		assert.Equal(t, "cmd/", node.Children[0].Path)
+		assert.True(t, node.Children[0].WildChild)
+		assert.False(t, node.Children[0].HasData)
+	}
+
+	// 写入失败时返回第一个错误
+	errWrite := errors.New("write failed")
+	assert.ErrorIs(t, pn.Dump(failWriter{errWrite}), errWrite)
+	assert.ErrorIs(t, pn.DOT(failWriter{errWrite}), errWrite)
+}
+
+type failWriter struct {
+	err error
+}
+
+func (w failWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
+func TestPathFindCaseInsensitivePathRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	segments := []string{"a", "ab", "B", "ä", "Ö", "x", ":p", ":q", "*rest"}
+	randomPath := func(wildcards bool) string {
+		var sb strings.Builder
+		n := 1 + r.Intn(4)
+		for i := 0; i < n; i++ {
+			segment := segments[r.Intn(len(segments))]
+			if !wildcards && (segment[0] == ':' || segment[0] == '*') {
+				segment = "v"
+			} else if segment[0] == '*' && i != n-1 {
+				segment = "c"
+			}
+			sb.WriteString("/" + segment)
+		}
+		if r.Intn(3) == 0 && !strings.Contains(sb.String(), "*") {
+			sb.WriteString("/")
+		}
+		return sb.String()
+	}
+
+	for i := 0; i < 2000; i++ {
+		pn := &PathNode[string]{}
+		var routes []string
+		for j := 0; j < 6; j++ {
+			route := randomPath(true)
+			if pn.TryAddNode(route, &route) == nil {
+				routes = append(routes, route)
+			}
+		}
+
+		for j := 0; j < 20; j++ {
+			path := randomPath(false)
+			if r.Intn(2) == 0 {
+				path = strings.ToUpper(path)
+			}
+
+			for _, fix := range []bool{false, true} {
+				out, found := pn.FindCaseInsensitivePath(path, fix)
+				if !found {
+					continue
+				}
+
+				// 找到的路径一定能匹配到已注册的数据
+				v := pn.GetValue(out, nil, nil)
+				if !assert.NotNil(t, v.Data, "routes %v path %q fix %v out %q", routes, path, fix, out) {
+					return
+				}
+				if !fix {
+					assert.True(t, strings.EqualFold(path, out), "routes %v path %q out %q", routes, path, out)
+				}
+			}
+		}
+	}
+}
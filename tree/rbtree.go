@@ -0,0 +1,373 @@
+package tree
+
+import "golang.org/x/exp/constraints"
+
+/*
+	红黑树实现的有序map(左倾红黑树), 插入、查找、删除均为O(log n)
+	每个节点记录子树大小, 支持按排名查找
+	ex:
+	rb := NewRBTree[string, int]()
+	rb.Put("b", 2)
+	rb.Put("a", 1)
+	rb.Ascend(func(k string, v int) bool { ... }) // a, b
+*/
+
+const (
+	red   = true
+	black = false
+)
+
+// rbNode 红黑树节点
+type rbNode[K, V any] struct {
+	key         K
+	value       V
+	left, right *rbNode[K, V]
+	color       bool
+	// size 以当前节点为根的子树的节点数量
+	size int
+}
+
+// RBTree 红黑树有序map, 非并发安全
+type RBTree[K, V any] struct {
+	root *rbNode[K, V]
+	less func(a, b K) bool
+	// augment 子节点变化后更新节点上的附加信息, 子节点的附加信息已是最新
+	augment func(n *rbNode[K, V])
+}
+
+// NewRBTree 构造函数, 键按自然顺序排列
+func NewRBTree[K constraints.Ordered, V any]() *RBTree[K, V] {
+	return NewRBTreeFunc[K, V](func(a, b K) bool {
+		return a < b
+	})
+}
+
+// NewRBTreeFunc 构造函数, less为键的比较函数
+func NewRBTreeFunc[K, V any](less func(a, b K) bool) *RBTree[K, V] {
+	if less == nil {
+		panic("less must not be nil")
+	}
+
+	return &RBTree[K, V]{
+		less: less,
+	}
+}
+
+func isRed[K, V any](n *rbNode[K, V]) bool {
+	return n != nil && n.color == red
+}
+
+func sizeOf[K, V any](n *rbNode[K, V]) int {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
+
+// compare 比较两个键, 返回-1、0、1
+func (rb *RBTree[K, V]) compare(a, b K) int {
+	switch {
+	case rb.less(a, b):
+		return -1
+	case rb.less(b, a):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Len 获取键值对数量
+func (rb *RBTree[K, V]) Len() int {
+	return sizeOf(rb.root)
+}
+
+// Get 获取键对应的值
+func (rb *RBTree[K, V]) Get(key K) (value V, ok bool) {
+	for n := rb.root; n != nil; {
+		switch rb.compare(key, n.key) {
+		case -1:
+			n = n.left
+		case 1:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+
+	return
+}
+
+// Put 设置键值对, 键已存在时覆盖值
+func (rb *RBTree[K, V]) Put(key K, value V) {
+	rb.root = rb.put(rb.root, key, value)
+	rb.root.color = black
+}
+
+func (rb *RBTree[K, V]) put(n *rbNode[K, V], key K, value V) *rbNode[K, V] {
+	if n == nil {
+		n = &rbNode[K, V]{key: key, value: value, color: red}
+		rb.update(n)
+		return n
+	}
+
+	switch rb.compare(key, n.key) {
+	case -1:
+		n.left = rb.put(n.left, key, value)
+	case 1:
+		n.right = rb.put(n.right, key, value)
+	default:
+		n.value = value
+	}
+
+	return rb.balance(n)
+}
+
+// Delete 删除键值对, 键不存在时返回false
+func (rb *RBTree[K, V]) Delete(key K) bool {
+	if _, ok := rb.Get(key); !ok {
+		return false
+	}
+
+	if !isRed(rb.root.left) && !isRed(rb.root.right) {
+		rb.root.color = red
+	}
+
+	rb.root = rb.delete(rb.root, key)
+	if rb.root != nil {
+		rb.root.color = black
+	}
+
+	return true
+}
+
+// delete 删除子树中的键, 调用前需确认键存在
+func (rb *RBTree[K, V]) delete(n *rbNode[K, V], key K) *rbNode[K, V] {
+	if rb.less(key, n.key) {
+		if !isRed(n.left) && !isRed(n.left.left) {
+			n = rb.moveRedLeft(n)
+		}
+		n.left = rb.delete(n.left, key)
+		return rb.balance(n)
+	}
+
+	if isRed(n.left) {
+		n = rb.rotateRight(n)
+	}
+
+	if rb.compare(key, n.key) == 0 && n.right == nil {
+		return nil
+	}
+
+	if !isRed(n.right) && !isRed(n.right.left) {
+		n = rb.moveRedRight(n)
+	}
+
+	if rb.compare(key, n.key) == 0 {
+		// 用右子树的最小节点替换当前节点
+		successor := n.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		n.key, n.value = successor.key, successor.value
+		n.right = rb.deleteMin(n.right)
+	} else {
+		n.right = rb.delete(n.right, key)
+	}
+
+	return rb.balance(n)
+}
+
+func (rb *RBTree[K, V]) deleteMin(n *rbNode[K, V]) *rbNode[K, V] {
+	if n.left == nil {
+		return nil
+	}
+
+	if !isRed(n.left) && !isRed(n.left.left) {
+		n = rb.moveRedLeft(n)
+	}
+	n.left = rb.deleteMin(n.left)
+
+	return rb.balance(n)
+}
+
+func (rb *RBTree[K, V]) rotateLeft(n *rbNode[K, V]) *rbNode[K, V] {
+	x := n.right
+	n.right = x.left
+	x.left = n
+	x.color = n.color
+	n.color = red
+	rb.update(n)
+	rb.update(x)
+
+	return x
+}
+
+func (rb *RBTree[K, V]) rotateRight(n *rbNode[K, V]) *rbNode[K, V] {
+	x := n.left
+	n.left = x.right
+	x.right = n
+	x.color = n.color
+	n.color = red
+	rb.update(n)
+	rb.update(x)
+
+	return x
+}
+
+func flipColors[K, V any](n *rbNode[K, V]) {
+	n.color = !n.color
+	n.left.color = !n.left.color
+	n.right.color = !n.right.color
+}
+
+func (rb *RBTree[K, V]) moveRedLeft(n *rbNode[K, V]) *rbNode[K, V] {
+	flipColors(n)
+	if isRed(n.right.left) {
+		n.right = rb.rotateRight(n.right)
+		n = rb.rotateLeft(n)
+		flipColors(n)
+	}
+
+	return n
+}
+
+func (rb *RBTree[K, V]) moveRedRight(n *rbNode[K, V]) *rbNode[K, V] {
+	flipColors(n)
+	if isRed(n.left.left) {
+		n = rb.rotateRight(n)
+		flipColors(n)
+	}
+
+	return n
+}
+
+// balance 恢复左倾红黑树的性质并更新子树大小
+func (rb *RBTree[K, V]) balance(n *rbNode[K, V]) *rbNode[K, V] {
+	if isRed(n.right) && !isRed(n.left) {
+		n = rb.rotateLeft(n)
+	}
+	if isRed(n.left) && isRed(n.left.left) {
+		n = rb.rotateRight(n)
+	}
+	if isRed(n.left) && isRed(n.right) {
+		flipColors(n)
+	}
+	rb.update(n)
+
+	return n
+}
+
+// update 根据子节点更新子树大小和附加信息
+func (rb *RBTree[K, V]) update(n *rbNode[K, V]) {
+	n.size = sizeOf(n.left) + sizeOf(n.right) + 1
+	if rb.augment != nil {
+		rb.augment(n)
+	}
+}
+
+// Min 获取最小的键值对
+func (rb *RBTree[K, V]) Min() (key K, value V, ok bool) {
+	n := rb.root
+	if n == nil {
+		return
+	}
+
+	for n.left != nil {
+		n = n.left
+	}
+
+	return n.key, n.value, true
+}
+
+// Max 获取最大的键值对
+func (rb *RBTree[K, V]) Max() (key K, value V, ok bool) {
+	n := rb.root
+	if n == nil {
+		return
+	}
+
+	for n.right != nil {
+		n = n.right
+	}
+
+	return n.key, n.value, true
+}
+
+// Ascend 按键从小到大遍历, fn返回false时停止遍历
+func (rb *RBTree[K, V]) Ascend(fn func(key K, value V) bool) {
+	rb.ascend(rb.root, fn)
+}
+
+func (rb *RBTree[K, V]) ascend(n *rbNode[K, V], fn func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	return rb.ascend(n.left, fn) && fn(n.key, n.value) && rb.ascend(n.right, fn)
+}
+
+// Range 按键从小到大遍历lo到hi之间(包含两端)的键值对, fn返回false时停止遍历
+func (rb *RBTree[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	rb.rangeNode(rb.root, lo, hi, fn)
+}
+
+func (rb *RBTree[K, V]) rangeNode(n *rbNode[K, V], lo, hi K, fn func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	afterLo := !rb.less(n.key, lo)
+	beforeHi := !rb.less(hi, n.key)
+	if afterLo && !rb.rangeNode(n.left, lo, hi, fn) {
+		return false
+	}
+	if afterLo && beforeHi && !fn(n.key, n.value) {
+		return false
+	}
+	if beforeHi {
+		return rb.rangeNode(n.right, lo, hi, fn)
+	}
+
+	return true
+}
+
+// Rank 获取小于key的键的数量
+func (rb *RBTree[K, V]) Rank(key K) int {
+	rank := 0
+	for n := rb.root; n != nil; {
+		switch rb.compare(key, n.key) {
+		case -1:
+			n = n.left
+		case 1:
+			rank += sizeOf(n.left) + 1
+			n = n.right
+		default:
+			return rank + sizeOf(n.left)
+		}
+	}
+
+	return rank
+}
+
+// Select 获取排名为rank(从0开始)的键值对, 即第rank+1小的键
+func (rb *RBTree[K, V]) Select(rank int) (key K, value V, ok bool) {
+	if rank < 0 || rank >= rb.Len() {
+		return
+	}
+
+	for n := rb.root; n != nil; {
+		leftSize := sizeOf(n.left)
+		switch {
+		case rank < leftSize:
+			n = n.left
+		case rank > leftSize:
+			rank -= leftSize + 1
+			n = n.right
+		default:
+			return n.key, n.value, true
+		}
+	}
+
+	return
+}
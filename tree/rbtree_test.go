@@ -0,0 +1,143 @@
+package tree
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// checkRBTree 检查左倾红黑树的性质和子树大小, 返回黑色高度
+func checkRBTree[K, V any](t *testing.T, rb *RBTree[K, V], n *rbNode[K, V]) int {
+	if n == nil {
+		return 0
+	}
+
+	assert.False(t, isRed(n.right), "right red link")
+	assert.False(t, isRed(n) && isRed(n.left), "two red links in a row")
+	assert.Equal(t, sizeOf(n.left)+sizeOf(n.right)+1, n.size)
+	if n.left != nil {
+		assert.True(t, rb.less(n.left.key, n.key))
+	}
+	if n.right != nil {
+		assert.True(t, rb.less(n.key, n.right.key))
+	}
+
+	left, right := checkRBTree(t, rb, n.left), checkRBTree(t, rb, n.right)
+	assert.Equal(t, left, right, "black height")
+	if !isRed(n) {
+		left++
+	}
+
+	return left
+}
+
+func TestRBTree(t *testing.T) {
+	rb := NewRBTree[int, string]()
+	_, _, ok := rb.Min()
+	assert.False(t, ok)
+	assert.False(t, rb.Delete(1))
+
+	r := rand.New(rand.NewSource(1))
+	m := map[int]string{}
+	for i := 0; i < 2000; i++ {
+		k := r.Intn(500)
+		if r.Intn(3) == 0 {
+			_, exists := m[k]
+			assert.Equal(t, exists, rb.Delete(k))
+			delete(m, k)
+		} else {
+			v := string(rune('a' + r.Intn(26)))
+			rb.Put(k, v)
+			m[k] = v
+		}
+		assert.False(t, isRed(rb.root))
+		if i%100 == 0 {
+			checkRBTree(t, rb, rb.root)
+		}
+	}
+	checkRBTree(t, rb, rb.root)
+	assert.Equal(t, len(m), rb.Len())
+
+	keys := make([]int, 0, len(m))
+	for k, v := range m {
+		keys = append(keys, k)
+		got, ok := rb.Get(k)
+		assert.True(t, ok)
+		assert.Equal(t, v, got)
+	}
+	sort.Ints(keys)
+
+	var ascend []int
+	rb.Ascend(func(k int, _ string) bool {
+		ascend = append(ascend, k)
+		return true
+	})
+	assert.Equal(t, keys, ascend)
+
+	for i, k := range keys {
+		assert.Equal(t, i, rb.Rank(k))
+		sk, sv, ok := rb.Select(i)
+		assert.True(t, ok)
+		assert.Equal(t, k, sk)
+		assert.Equal(t, m[k], sv)
+	}
+	assert.Equal(t, 0, rb.Rank(-1))
+	assert.Equal(t, len(keys), rb.Rank(1000))
+	_, _, ok = rb.Select(len(keys))
+	assert.False(t, ok)
+	_, _, ok = rb.Select(-1)
+	assert.False(t, ok)
+
+	k, _, _ := rb.Min()
+	assert.Equal(t, keys[0], k)
+	k, _, _ = rb.Max()
+	assert.Equal(t, keys[len(keys)-1], k)
+
+	var inRange []int
+	rb.Range(100, 200, func(k int, _ string) bool {
+		inRange = append(inRange, k)
+		return true
+	})
+	var want []int
+	for _, k := range keys {
+		if 100 <= k && k <= 200 {
+			want = append(want, k)
+		}
+	}
+	assert.Equal(t, want, inRange)
+
+	// 提前终止
+	count := 0
+	rb.Range(0, 1000, func(int, string) bool {
+		count++
+		return count < 3
+	})
+	assert.Equal(t, 3, count)
+
+	for _, k := range keys {
+		assert.True(t, rb.Delete(k))
+	}
+	assert.Zero(t, rb.Len())
+	assert.Nil(t, rb.root)
+}
+
+func TestRBTreeFunc(t *testing.T) {
+	rb := NewRBTreeFunc[string, int](func(a, b string) bool {
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+	rb.Put("b", 2)
+	rb.Put("A", 1)
+	rb.Put("a", 3)
+
+	assert.Equal(t, 2, rb.Len())
+	v, ok := rb.Get("A")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	k, _, _ := rb.Select(0)
+	assert.Equal(t, "A", k)
+
+	assert.Panics(t, func() { NewRBTreeFunc[string, int](nil) })
+}
@@ -0,0 +1,107 @@
+package tree
+
+import (
+	"strings"
+	"sync"
+)
+
+// Router 路由器, 按方法(或命名空间)分别维护一棵路径树
+// Handle 需在 Lookup 之前完成, 注册完成后 Lookup 可被多个goroutine并发调用
+type Router[T any] struct {
+	trees       map[string]*PathNode[T]
+	names       map[string]*namedRoute
+	maxParams   uint16
+	maxSections uint16
+
+	paramsPool  sync.Pool
+	skippedPool sync.Pool
+}
+
+// NewRouter 构造函数
+func NewRouter[T any]() *Router[T] {
+	r := &Router[T]{
+		trees: make(map[string]*PathNode[T]),
+		names: make(map[string]*namedRoute),
+	}
+	r.paramsPool.New = func() any {
+		ps := make(Params, 0, r.maxParams)
+		return &ps
+	}
+	r.skippedPool.New = func() any {
+		sn := make([]skippedNode[T], 0, r.maxSections)
+		return &sn
+	}
+
+	return r
+}
+
+// Handle 注册路径数据, 冲突时panic
+func (r *Router[T]) Handle(method, path string, t *T) {
+	if method == "" {
+		panic("method must not be empty")
+	}
+	if len(path) < 1 || path[0] != '/' {
+		panic("path must begin with '/' in path '" + path + "'")
+	}
+
+	root := r.trees[method]
+	if root == nil {
+		root = new(PathNode[T])
+		r.trees[method] = root
+	}
+	root.AddNode(path, t)
+
+	if paramsCount := countParams(path); paramsCount > r.maxParams {
+		r.maxParams = paramsCount
+	}
+	if sectionsCount := countSections(path); sectionsCount > r.maxSections {
+		r.maxSections = sectionsCount
+	}
+}
+
+// Lookup 查找路径数据
+// 匹配成功且包含通配符参数时, value.Params 来自内部缓冲池, 使用完毕后可通过 PutParams 归还
+func (r *Router[T]) Lookup(method, path string) (value NodeValue[T]) {
+	root := r.trees[method]
+	if root == nil {
+		return
+	}
+
+	var params *Params
+	if r.maxParams > 0 {
+		params = r.paramsPool.Get().(*Params)
+	}
+	skippedNodes := r.skippedPool.Get().(*[]skippedNode[T])
+
+	value = root.GetValue(path, params, skippedNodes)
+
+	*skippedNodes = (*skippedNodes)[:0]
+	r.skippedPool.Put(skippedNodes)
+
+	if params != nil && (value.Params == nil || value.Data == nil) {
+		r.PutParams(params)
+		value.Params = nil
+	}
+
+	return
+}
+
+// PutParams 归还 Lookup 返回的参数列表, 归还后不可再使用
+func (r *Router[T]) PutParams(ps *Params) {
+	if ps == nil {
+		return
+	}
+
+	*ps = (*ps)[:0]
+	r.paramsPool.Put(ps)
+}
+
+// countParams 统计路径中通配符的数量
+func countParams(path string) uint16 {
+	return uint16(strings.Count(path, ":") + strings.Count(path, "*"))
+}
+
+// countSections 统计路径段的数量
+func countSections(path string) uint16 {
+	return uint16(strings.Count(path, "/"))
+}
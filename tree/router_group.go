@@ -0,0 +1,79 @@
+package tree
+
+import "strings"
+
+/*
+	路由分组, 为注册的路径统一添加前缀, 并用中间件包装注册的数据
+	ex:
+	api := r.Group("/api", logging)
+	v1 := api.Group("/v1", auth)
+	v1.Handle("GET", "/users/:id", &h) // 注册 "/api/v1/users/:id", 数据为 logging(auth(h))
+*/
+
+// Middleware 中间件, 包装数据并返回包装后的数据
+type Middleware[T any] func(T) T
+
+// RouteGroup 路由分组
+type RouteGroup[T any] struct {
+	prefix      string
+	middlewares []Middleware[T]
+	handle      func(method, path string, t *T)
+}
+
+// NewRouteGroup 以注册函数构造根分组, 用于在 Router 之外的路由器上使用分组
+func NewRouteGroup[T any](handle func(method, path string, t *T)) *RouteGroup[T] {
+	return &RouteGroup[T]{
+		handle: handle,
+	}
+}
+
+// Group 新建路由分组
+func (r *Router[T]) Group(prefix string, middlewares ...Middleware[T]) *RouteGroup[T] {
+	return NewRouteGroup(r.Handle).Group(prefix, middlewares...)
+}
+
+// Group 新建子分组, 前缀追加在当前分组的前缀之后, 当前分组的中间件包装在子分组的中间件之外
+func (g *RouteGroup[T]) Group(prefix string, middlewares ...Middleware[T]) *RouteGroup[T] {
+	list := make([]Middleware[T], 0, len(g.middlewares)+len(middlewares))
+	list = append(list, g.middlewares...)
+	list = append(list, middlewares...)
+
+	return &RouteGroup[T]{
+		prefix:      joinPath(g.prefix, prefix),
+		middlewares: list,
+		handle:      g.handle,
+	}
+}
+
+// Use 追加中间件, 只对之后注册的路径和新建的子分组生效
+func (g *RouteGroup[T]) Use(middlewares ...Middleware[T]) {
+	g.middlewares = append(g.middlewares[:len(g.middlewares):len(g.middlewares)], middlewares...)
+}
+
+// Prefix 获取分组的完整前缀
+func (g *RouteGroup[T]) Prefix() string {
+	return g.prefix
+}
+
+// Handle 注册路径数据, 实际注册的路径为分组前缀加path, 数据为中间件依次包装后的结果
+// 先添加的中间件在最外层; t为nil时不做包装
+func (g *RouteGroup[T]) Handle(method, path string, t *T) {
+	if t != nil {
+		wrapped := *t
+		for i := len(g.middlewares) - 1; i >= 0; i-- {
+			wrapped = g.middlewares[i](wrapped)
+		}
+		t = &wrapped
+	}
+
+	g.handle(method, joinPath(g.prefix, path), t)
+}
+
+// joinPath 拼接路径前缀和相对路径, 保留相对路径末尾的'/'
+func joinPath(prefix, path string) string {
+	if path == "" {
+		return prefix
+	}
+
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
@@ -0,0 +1,203 @@
+package tree
+
+import (
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	r := NewRouter[string]()
+	routes := []struct {
+		method, path string
+	}{
+		{"GET", "/"},
+		{"GET", "/users/:id"},
+		{"GET", "/users/new"},
+		{"GET", "/files/*filepath"},
+		{"POST", "/users"},
+	}
+	for i := range routes {
+		r.Handle(routes[i].method, routes[i].path, &routes[i].path)
+	}
+
+	v := r.Lookup("GET", "/users/new")
+	if assert.NotNil(t, v.Data) {
+		assert.Equal(t, "/users/new", *v.Data)
+	}
+	assert.Nil(t, v.Params)
+
+	v = r.Lookup("GET", "/users/42")
+	if assert.NotNil(t, v.Data) && assert.NotNil(t, v.Params) {
+		assert.Equal(t, "/users/:id", v.FullPath)
+		assert.Equal(t, "42", v.Params.ByName("id"))
+	}
+	r.PutParams(v.Params)
+
+	v = r.Lookup("POST", "/users")
+	if assert.NotNil(t, v.Data) {
+		assert.Equal(t, "/users", *v.Data)
+	}
+
+	v = r.Lookup("POST", "/users/42")
+	assert.Nil(t, v.Data)
+	assert.Nil(t, v.Params)
+
+	v = r.Lookup("DELETE", "/users")
+	assert.Nil(t, v.Data)
+
+	assert.Panics(t, func() { r.Handle("", "/a", nil) })
+	assert.Panics(t, func() { r.Handle("GET", "a", nil) })
+
+	// sync.Pool 在 race 模式下会随机丢弃对象
+	if !raceEnabled {
+		allocs := testing.AllocsPerRun(100, func() {
+			r.Lookup("GET", "/users/new")
+		})
+		assert.Zero(t, allocs)
+
+		allocs = testing.AllocsPerRun(100, func() {
+			v := r.Lookup("GET", "/files/a/b.txt")
+			r.PutParams(v.Params)
+		})
+		assert.Zero(t, allocs)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				v := r.Lookup("GET", "/files/a/b.txt")
+				assert.Equal(t, "/a/b.txt", v.Params.ByName("filepath"))
+				r.PutParams(v.Params)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRouterGroup(t *testing.T) {
+	r := NewRouter[string]()
+	wrap := func(name string) Middleware[string] {
+		return func(s string) string {
+			return name + "(" + s + ")"
+		}
+	}
+
+	api := r.Group("/api/", wrap("log"))
+	v1 := api.Group("v1", wrap("auth"), wrap("cors"))
+	api.Use(wrap("late"))
+
+	handlers := []string{"users", "user", "index", "health"}
+	v1.Handle("GET", "/users/", &handlers[0])
+	v1.Handle("GET", "/users/:id", &handlers[1])
+	api.Handle("GET", "", &handlers[2])
+	r.Group("").Handle("GET", "/health", &handlers[3])
+	v1.Handle("DELETE", "/users/:id", nil)
+
+	assert.Equal(t, "/api/v1", v1.Prefix())
+
+	tests := map[string]string{
+		"/api/v1/users/":   "log(auth(cors(users)))",
+		"/api/v1/users/42": "log(auth(cors(user)))",
+		"/api/":            "log(late(index))",
+		"/health":          "health",
+	}
+	for path, data := range tests {
+		v := r.Lookup("GET", path)
+		if assert.NotNil(t, v.Data, path) {
+			assert.Equal(t, data, *v.Data, path)
+		}
+		r.PutParams(v.Params)
+	}
+	assert.Nil(t, r.Lookup("DELETE", "/api/v1/users/42").Data)
+	assert.Equal(t, "users", handlers[0])
+
+	// 父分组之后添加的中间件不影响已创建的子分组
+	v1.Handle("GET", "/status", &handlers[3])
+	v := r.Lookup("GET", "/api/v1/status")
+	if assert.NotNil(t, v.Data) {
+		assert.Equal(t, "log(auth(cors(health)))", *v.Data)
+	}
+
+	var registered []string
+	g := NewRouteGroup(func(method, path string, t *string) {
+		registered = append(registered, method+" "+path+" "+*t)
+	}).Group("/admin", wrap("admin"))
+	g.Handle("POST", "/login", &handlers[2])
+	assert.Equal(t, []string{"POST /admin/login admin(index)"}, registered)
+}
+
+func TestRouterURL(t *testing.T) {
+	r := NewRouter[string]()
+	data := "data"
+	r.HandleNamed("user.show", "GET", "/users/:id<int>", &data)
+	r.HandleNamed("user.post", "GET", "/users/:id/posts/:slug", &data)
+	r.HandleNamed("static", "GET", "/files/*filepath", &data)
+	r.HandleNamed("index", "GET", "/", &data)
+	r.HandleNamed("dup", "GET", "/a/:id/b/:id", &data)
+
+	tests := []struct {
+		name  string
+		pairs []string
+		url   string
+	}{
+		{"user.show", []string{"id", "42"}, "/users/42"},
+		{"user.post", []string{"slug", "hello world", "id", "7"}, "/users/7/posts/hello%20world"},
+		{"static", []string{"filepath", "/css/a b.css"}, "/files/css/a%20b.css"},
+		{"static", []string{"filepath", "js/app.js"}, "/files/js/app.js"},
+		{"static", []string{"filepath", ""}, "/files/"},
+		{"index", nil, "/"},
+		{"dup", []string{"id", "1"}, "/a/1/b/1"},
+	}
+	for _, test := range tests {
+		u, err := r.URL(test.name, test.pairs...)
+		if assert.NoError(t, err, test.url) {
+			assert.Equal(t, test.url, u)
+		}
+
+		// 生成的URL解码后可以匹配到原路由
+		path, err := url.PathUnescape(test.url)
+		assert.NoError(t, err)
+		v := r.Lookup("GET", path)
+		assert.NotNil(t, v.Data, test.url)
+		r.PutParams(v.Params)
+	}
+
+	_, err := r.URL("user.delete", "id", "42")
+	assert.ErrorIs(t, err, ErrUnknownRoute)
+
+	for _, pairs := range [][]string{
+		{"id"},
+		{},
+		{"id", "abc"},
+		{"id", ""},
+		{"id", "42", "page", "2"},
+	} {
+		_, err = r.URL("user.show", pairs...)
+		assert.ErrorIs(t, err, ErrInvalidURLParams, pairs)
+	}
+	_, err = r.URL("user.post", "id", "7", "slug", "hello/2")
+	assert.ErrorIs(t, err, ErrInvalidURLParams)
+	for _, pairs := range [][]string{
+		{"id", "1", "id", "2"},
+		{"id", "1", "", "2"},
+	} {
+		_, err = r.URL("dup", pairs...)
+		assert.ErrorIs(t, err, ErrInvalidURLParams, pairs)
+	}
+
+	method, path, ok := r.Route("user.post")
+	assert.True(t, ok)
+	assert.Equal(t, "GET", method)
+	assert.Equal(t, "/users/:id/posts/:slug", path)
+	_, _, ok = r.Route("user.delete")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { r.HandleNamed("user.show", "POST", "/users/:id", &data) })
+	assert.Panics(t, func() { r.HandleNamed("", "POST", "/users/:id", &data) })
+}
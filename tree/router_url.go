@@ -0,0 +1,153 @@
+package tree
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+/*
+	命名路由, 根据路由名和参数值反向生成URL
+	ex:
+	r.HandleNamed("user.show", "GET", "/users/:id<int>", &h)
+	r.URL("user.show", "id", "42") // "/users/42"
+*/
+
+// namedRoute 命名路由, 注册时将路径拆分为静态部分和通配符
+type namedRoute struct {
+	method    string
+	path      string
+	separator string
+	parts     []urlPart
+}
+
+// urlPart 路径的组成部分, key为空时为静态部分
+type urlPart struct {
+	static   string
+	key      string
+	catchAll bool
+	match    func(string) bool
+}
+
+// HandleNamed 以指定名称注册路径数据, 名称为空、重复或路径冲突时panic
+func (r *Router[T]) HandleNamed(name, method, path string, t *T) {
+	if name == "" {
+		panic("route name must not be empty")
+	}
+	if _, exists := r.names[name]; exists {
+		panic("route name '" + name + "' is already registered")
+	}
+
+	r.Handle(method, path, t)
+	r.names[name] = newNamedRoute(&defaultPathConfig, method, path)
+}
+
+// Route 获取命名路由注册时的请求方法和路径
+func (r *Router[T]) Route(name string) (method, path string, ok bool) {
+	route, ok := r.names[name]
+	if !ok {
+		return "", "", false
+	}
+
+	return route.method, route.path, true
+}
+
+// URL 根据路由名和参数生成URL, pairs为交替出现的参数名和参数值
+// 所有通配符都必须提供参数值, 同名的通配符使用同一个值, 命名参数的值需满足约束且不能包含'/', 参数值会被转义; 全匹配参数中的'/'保持不变
+// 路由不存在时返回 ErrUnknownRoute, 参数缺失、多余或不合法时返回 ErrInvalidURLParams
+func (r *Router[T]) URL(name string, pairs ...string) (string, error) {
+	route, ok := r.names[name]
+	if !ok {
+		return "", fmt.Errorf("%w '%s'", ErrUnknownRoute, name)
+	}
+
+	if len(pairs)%2 != 0 {
+		return "", fmt.Errorf("%w: odd number of key-value pairs for route '%s'", ErrInvalidURLParams, name)
+	}
+
+	return route.build(pairs)
+}
+
+// newNamedRoute 拆分已通过检查的路径
+func newNamedRoute(cfg *pathConfig, method, path string) *namedRoute {
+	route := &namedRoute{
+		method:    method,
+		path:      path,
+		separator: cfg.separator,
+	}
+
+	for rest := path; ; {
+		wildcard, i, _ := cfg.findWildcard(rest)
+		if i < 0 {
+			route.parts = append(route.parts, urlPart{static: rest})
+			return route
+		}
+
+		route.parts = append(route.parts, urlPart{static: rest[:i]})
+		if wildcard[0] == cfg.catchAll {
+			route.parts = append(route.parts, urlPart{key: wildcard[1:], catchAll: true})
+		} else {
+			name, constraint, _ := splitParam(wildcard)
+			match, _ := compileConstraint(constraint)
+			route.parts = append(route.parts, urlPart{key: name, match: match})
+		}
+		rest = rest[i+len(wildcard):]
+	}
+}
+
+// build 填充参数生成URL
+func (route *namedRoute) build(pairs []string) (string, error) {
+	values := make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		if !route.hasKey(pairs[i]) {
+			return "", fmt.Errorf("%w: unknown key '%s' for path '%s'", ErrInvalidURLParams, pairs[i], route.path)
+		}
+		if _, exists := values[pairs[i]]; exists {
+			return "", fmt.Errorf("%w: duplicate key '%s' for path '%s'", ErrInvalidURLParams, pairs[i], route.path)
+		}
+		values[pairs[i]] = pairs[i+1]
+	}
+
+	var sb strings.Builder
+	for _, part := range route.parts {
+		if part.key == "" {
+			sb.WriteString(part.static)
+			continue
+		}
+
+		value, found := values[part.key]
+		if !found {
+			return "", fmt.Errorf("%w: missing value for '%s' in path '%s'", ErrInvalidURLParams, part.key, route.path)
+		}
+
+		if part.catchAll {
+			// 与匹配结果保持一致, 全匹配参数的值可以带有开头的'/'
+			segments := strings.Split(strings.TrimPrefix(value, route.separator), route.separator)
+			for i := range segments {
+				segments[i] = url.PathEscape(segments[i])
+			}
+			sb.WriteString(strings.Join(segments, route.separator))
+			continue
+		}
+
+		// 命名参数的值在分隔符处截断, 包含分隔符的值无法匹配回原路由
+		if value == "" || strings.Contains(value, route.separator) || (part.match != nil && !part.match(value)) {
+			return "", fmt.Errorf("%w: value '%s' does not satisfy '%s' in path '%s'",
+				ErrInvalidURLParams, value, part.key, route.path)
+		}
+		sb.WriteString(url.PathEscape(value))
+	}
+
+	return sb.String(), nil
+}
+
+// hasKey 路径中是否有名为key的通配符
+func (route *namedRoute) hasKey(key string) bool {
+	for _, part := range route.parts {
+		if part.key != "" && part.key == key {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,229 @@
+package tree
+
+/*
+	线段树, 维护数组的区间聚合值, combine需满足结合律, identity为combine的单位元
+	单点修改、区间赋值、区间加法(懒标记)、区间查询均为O(log n), 区间均为左闭右开[lo, hi)
+	ex:
+	st := NewSegmentTree([]int{5, 2, 8}, math.Min[int], stdmath.MaxInt,
+		WithRangeAdd(func(a, b int) int { return a + b }, func(agg, delta, _ int) int { return agg + delta }))
+	st.Query(0, 2)     // 2
+	st.Assign(1, 3, 9) // [5 9 9]
+	st.Add(0, 2, -1)   // [4 8 9]
+	st.Query(0, 3)     // 4
+*/
+
+// SegmentOption 线段树配置项
+type SegmentOption[T any] func(*SegmentTree[T])
+
+// WithRangeAdd 开启区间加法
+// add为两个值相加, apply为区间内每个值都加上delta后新的聚合值, agg为原聚合值, length为区间长度
+// 如求和时apply为agg+delta*length, 求最值时为agg+delta
+func WithRangeAdd[T any](add func(a, b T) T, apply func(agg, delta T, length int) T) SegmentOption[T] {
+	return func(st *SegmentTree[T]) {
+		st.add, st.apply = add, apply
+	}
+}
+
+// segmentLazy 尚未下推到子节点的区间修改, 先赋值再加上delta
+type segmentLazy[T any] struct {
+	assign    T
+	delta     T
+	hasAssign bool
+	hasDelta  bool
+}
+
+// SegmentTree 线段树, 非并发安全
+// 默认只支持区间赋值, 区间加法需通过 WithRangeAdd 开启
+type SegmentTree[T any] struct {
+	n        int
+	combine  func(a, b T) T
+	identity T
+	add      func(a, b T) T
+	apply    func(agg, delta T, length int) T
+	// tree 节点i的子节点为2i+1和2i+2
+	tree []T
+	lazy []segmentLazy[T]
+}
+
+// NewSegmentTree 构造函数, 复制list构建线段树, combine为nil时panic
+func NewSegmentTree[T any](list []T, combine func(a, b T) T, identity T, options ...SegmentOption[T]) *SegmentTree[T] {
+	if combine == nil {
+		panic("combine must not be nil")
+	}
+
+	st := &SegmentTree[T]{
+		n:        len(list),
+		combine:  combine,
+		identity: identity,
+		tree:     make([]T, 4*len(list)),
+		lazy:     make([]segmentLazy[T], 4*len(list)),
+	}
+	for _, option := range options {
+		option(st)
+	}
+	if (st.add == nil) != (st.apply == nil) {
+		panic("add and apply must both be set")
+	}
+
+	if st.n > 0 {
+		st.build(list, 0, 0, st.n)
+	}
+
+	return st
+}
+
+func (st *SegmentTree[T]) build(list []T, i, lo, hi int) {
+	if hi-lo == 1 {
+		st.tree[i] = list[lo]
+		return
+	}
+
+	mid := (lo + hi) / 2
+	st.build(list, 2*i+1, lo, mid)
+	st.build(list, 2*i+2, mid, hi)
+	st.tree[i] = st.combine(st.tree[2*i+1], st.tree[2*i+2])
+}
+
+// Len 获取数组长度
+func (st *SegmentTree[T]) Len() int {
+	return st.n
+}
+
+// repeat 计算count个t的聚合值
+func (st *SegmentTree[T]) repeat(t T, count int) T {
+	result := st.identity
+	for ; count > 0; count >>= 1 {
+		if count&1 == 1 {
+			result = st.combine(result, t)
+		}
+		t = st.combine(t, t)
+	}
+
+	return result
+}
+
+// assignNode 将节点i对应的区间全部赋值为t, 之前未下推的修改被覆盖
+func (st *SegmentTree[T]) assignNode(i, length int, t T) {
+	st.tree[i] = st.repeat(t, length)
+	st.lazy[i] = segmentLazy[T]{assign: t, hasAssign: true}
+}
+
+// addNode 将节点i对应的区间都加上delta
+func (st *SegmentTree[T]) addNode(i, length int, delta T) {
+	st.tree[i] = st.apply(st.tree[i], delta, length)
+
+	lazy := &st.lazy[i]
+	switch {
+	case lazy.hasAssign:
+		// 赋值后再加等价于赋值为相加后的值
+		lazy.assign = st.add(lazy.assign, delta)
+	case lazy.hasDelta:
+		lazy.delta = st.add(lazy.delta, delta)
+	default:
+		lazy.delta, lazy.hasDelta = delta, true
+	}
+}
+
+// pushDown 将节点i的懒标记下推到子节点
+func (st *SegmentTree[T]) pushDown(i, lo, mid, hi int) {
+	lazy := st.lazy[i]
+	if lazy.hasAssign {
+		st.assignNode(2*i+1, mid-lo, lazy.assign)
+		st.assignNode(2*i+2, hi-mid, lazy.assign)
+	}
+	if lazy.hasDelta {
+		st.addNode(2*i+1, mid-lo, lazy.delta)
+		st.addNode(2*i+2, hi-mid, lazy.delta)
+	}
+
+	st.lazy[i] = segmentLazy[T]{}
+}
+
+// Get 获取下标为i的数据, 下标越界时返回false
+func (st *SegmentTree[T]) Get(i int) (t T, ok bool) {
+	if i < 0 || i >= st.n {
+		return
+	}
+
+	return st.Query(i, i+1), true
+}
+
+// Set 设置下标为i的数据, 下标越界时返回false
+func (st *SegmentTree[T]) Set(i int, t T) bool {
+	return st.Assign(i, i+1, t)
+}
+
+// Assign 将[lo, hi)内的数据全部赋值为t, 区间为空或越界时返回false
+func (st *SegmentTree[T]) Assign(lo, hi int, t T) bool {
+	if lo < 0 || hi > st.n || lo >= hi {
+		return false
+	}
+
+	st.update(0, 0, st.n, lo, hi, func(i, length int) {
+		st.assignNode(i, length, t)
+	})
+	return true
+}
+
+// Add 将[lo, hi)内的数据都加上delta, 未通过 WithRangeAdd 开启、区间为空或越界时返回false
+func (st *SegmentTree[T]) Add(lo, hi int, delta T) bool {
+	if st.add == nil || lo < 0 || hi > st.n || lo >= hi {
+		return false
+	}
+
+	st.update(0, 0, st.n, lo, hi, func(i, length int) {
+		st.addNode(i, length, delta)
+	})
+	return true
+}
+
+// update 对被[qlo, qhi)完全覆盖的节点执行fn, 并更新沿途节点的聚合值
+func (st *SegmentTree[T]) update(i, lo, hi, qlo, qhi int, fn func(i, length int)) {
+	if qlo <= lo && hi <= qhi {
+		fn(i, hi-lo)
+		return
+	}
+
+	mid := (lo + hi) / 2
+	st.pushDown(i, lo, mid, hi)
+	if qlo < mid {
+		st.update(2*i+1, lo, mid, qlo, qhi, fn)
+	}
+	if qhi > mid {
+		st.update(2*i+2, mid, hi, qlo, qhi, fn)
+	}
+	st.tree[i] = st.combine(st.tree[2*i+1], st.tree[2*i+2])
+}
+
+// Query 获取[lo, hi)内数据的聚合值, 区间为空时返回identity, 越界部分被忽略
+func (st *SegmentTree[T]) Query(lo, hi int) T {
+	if lo < 0 {
+		lo = 0
+	}
+	if hi > st.n {
+		hi = st.n
+	}
+	if lo >= hi {
+		return st.identity
+	}
+
+	return st.query(0, 0, st.n, lo, hi)
+}
+
+func (st *SegmentTree[T]) query(i, lo, hi, qlo, qhi int) T {
+	if qlo <= lo && hi <= qhi {
+		return st.tree[i]
+	}
+
+	mid := (lo + hi) / 2
+	st.pushDown(i, lo, mid, hi)
+	result := st.identity
+	if qlo < mid {
+		result = st.query(2*i+1, lo, mid, qlo, qhi)
+	}
+	if qhi > mid {
+		result = st.combine(result, st.query(2*i+2, mid, hi, qlo, qhi))
+	}
+
+	return result
+}
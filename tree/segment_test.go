@@ -0,0 +1,123 @@
+package tree
+
+import (
+	stdmath "math"
+	"math/rand"
+	"testing"
+
+	"github.com/liuxh-go/chopper/math"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegmentTree(t *testing.T) {
+	st := NewSegmentTree([]int{5, 2, 8, 6, 3}, math.Min[int], stdmath.MaxInt)
+	assert.Equal(t, 5, st.Len())
+	assert.Equal(t, 2, st.Query(0, 5))
+	assert.Equal(t, 6, st.Query(2, 4))
+	assert.Equal(t, 2, st.Query(-1, 100))
+	assert.Equal(t, stdmath.MaxInt, st.Query(3, 3))
+
+	assert.True(t, st.Assign(1, 4, 9))
+	assert.Equal(t, 5, st.Query(0, 3))
+	assert.Equal(t, 9, st.Query(1, 4))
+	assert.True(t, st.Set(2, 1))
+	assert.Equal(t, 1, st.Query(1, 4))
+	v, ok := st.Get(3)
+	assert.True(t, ok)
+	assert.Equal(t, 9, v)
+
+	assert.False(t, st.Assign(3, 3, 0))
+	assert.False(t, st.Assign(-1, 2, 0))
+	assert.False(t, st.Set(5, 0))
+	_, ok = st.Get(-1)
+	assert.False(t, ok)
+
+	assert.False(t, st.Add(0, 5, 1))
+
+	add := func(a, b int) int { return a + b }
+	sum := NewSegmentTree([]int{1, 2, 3, 4}, add, 0, WithRangeAdd(add, func(agg, delta, length int) int {
+		return agg + delta*length
+	}))
+	assert.True(t, sum.Add(0, 3, 10))
+	assert.True(t, sum.Assign(2, 4, 1))
+	assert.True(t, sum.Add(1, 4, 2))
+	assert.Equal(t, 11+14+3+3, sum.Query(0, 4))
+	v, _ = sum.Get(1)
+	assert.Equal(t, 14, v)
+	assert.False(t, sum.Add(2, 2, 1))
+
+	empty := NewSegmentTree(nil, add, 0)
+	assert.Equal(t, 0, empty.Query(0, 1))
+	assert.Panics(t, func() { NewSegmentTree[int](nil, nil, 0) })
+}
+
+func TestSegmentTreeRandom(t *testing.T) {
+	gcd := func(a, b int) int {
+		for b != 0 {
+			a, b = b, a%b
+		}
+		return a
+	}
+	add := func(a, b int) int { return a + b }
+	shift := func(agg, delta, _ int) int { return agg + delta }
+	combines := map[string]struct {
+		combine  func(a, b int) int
+		identity int
+		options  []SegmentOption[int]
+	}{
+		"sum": {add, 0, []SegmentOption[int]{WithRangeAdd(add, func(agg, delta, length int) int {
+			return agg + delta*length
+		})}},
+		"min": {math.Min[int], stdmath.MaxInt, []SegmentOption[int]{WithRangeAdd(add, shift)}},
+		"max": {math.Max[int], stdmath.MinInt, []SegmentOption[int]{WithRangeAdd(add, shift)}},
+		"gcd": {gcd, 0, nil},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for name, c := range combines {
+		list := make([]int, 100)
+		for i := range list {
+			list[i] = r.Intn(100)
+		}
+		st := NewSegmentTree(list, c.combine, c.identity, c.options...)
+		list = append([]int(nil), list...)
+
+		// 区间赋值、单点修改和区间加法交替作用在重叠的区间上
+		for i := 0; i < 3000; i++ {
+			lo := r.Intn(len(list))
+			hi := lo + 1 + r.Intn(len(list)-lo)
+			switch r.Intn(4) {
+			case 0:
+				v := r.Intn(100)
+				assert.True(t, st.Assign(lo, hi, v))
+				for j := lo; j < hi; j++ {
+					list[j] = v
+				}
+			case 1:
+				v := r.Intn(100)
+				assert.True(t, st.Set(lo, v))
+				list[lo] = v
+			case 2:
+				delta := r.Intn(21) - 10
+				if !assert.Equal(t, c.options != nil, st.Add(lo, hi, delta), name) || c.options == nil {
+					continue
+				}
+				for j := lo; j < hi; j++ {
+					list[j] += delta
+				}
+			default:
+				want := c.identity
+				for _, v := range list[lo:hi] {
+					want = c.combine(want, v)
+				}
+				assert.Equal(t, want, st.Query(lo, hi), name)
+			}
+		}
+
+		for i, v := range list {
+			got, ok := st.Get(i)
+			assert.True(t, ok)
+			assert.Equal(t, v, got, name)
+		}
+	}
+}
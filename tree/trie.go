@@ -0,0 +1,297 @@
+package tree
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/liuxh-go/chopper/math"
+)
+
+/*
+	字典树, 以字符(rune)为单位存储任意字符串键
+	ex:
+	tr := NewTrie[int]()
+	tr.Put("tea", 3)
+	tr.Put("ten", 5)
+	tr.KeysWithPrefix("te")      // [tea ten]
+	tr.FuzzySearch("tan", 1)     // [ten]
+
+	键按字符的Unicode码点排序; 不合法的UTF-8字节按单个字节处理, 排在所有合法字符之前
+*/
+
+// trieNode 字典树节点
+type trieNode[V any] struct {
+	r rune
+	// children 子节点, 按字符排序
+	children []*trieNode[V]
+	// key 节点存有数据时为完整的键
+	key      string
+	value    V
+	hasValue bool
+}
+
+// Trie 字典树, 零值即可使用, 非并发安全
+type Trie[V any] struct {
+	root trieNode[V]
+	size int
+}
+
+// NewTrie 构造函数
+func NewTrie[V any]() *Trie[V] {
+	return &Trie[V]{}
+}
+
+// nextRune 获取字符串的第一个字符, 不合法的字节转换为负数以保证不同的字节互不相同
+func nextRune(s string) (rune, int) {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError && size == 1 {
+		return rune(s[0]) - 0x100, 1
+	}
+
+	return r, size
+}
+
+// child 获取字符对应的子节点及其应在的下标
+func (tn *trieNode[V]) child(r rune) (*trieNode[V], int) {
+	i := sort.Search(len(tn.children), func(i int) bool {
+		return tn.children[i].r >= r
+	})
+	if i < len(tn.children) && tn.children[i].r == r {
+		return tn.children[i], i
+	}
+
+	return nil, i
+}
+
+// find 获取键对应的节点, 不存在时返回nil
+func (tr *Trie[V]) find(key string) *trieNode[V] {
+	n := &tr.root
+	for len(key) > 0 && n != nil {
+		r, size := nextRune(key)
+		n, _ = n.child(r)
+		key = key[size:]
+	}
+
+	return n
+}
+
+// Len 获取键的数量
+func (tr *Trie[V]) Len() int {
+	return tr.size
+}
+
+// Put 设置键值对, 键已存在时覆盖值
+func (tr *Trie[V]) Put(key string, value V) {
+	n := &tr.root
+	for rest := key; len(rest) > 0; {
+		r, size := nextRune(rest)
+		child, i := n.child(r)
+		if child == nil {
+			child = &trieNode[V]{r: r}
+			n.children = append(n.children, nil)
+			copy(n.children[i+1:], n.children[i:])
+			n.children[i] = child
+		}
+
+		n = child
+		rest = rest[size:]
+	}
+
+	if !n.hasValue {
+		tr.size++
+	}
+	n.key, n.value, n.hasValue = key, value, true
+}
+
+// Get 获取键对应的值
+func (tr *Trie[V]) Get(key string) (value V, ok bool) {
+	n := tr.find(key)
+	if n == nil || !n.hasValue {
+		return
+	}
+
+	return n.value, true
+}
+
+// Delete 删除键, 并删除因此不再有数据的节点, 键不存在时返回false
+func (tr *Trie[V]) Delete(key string) bool {
+	// 记录沿途的节点, 用于自下而上删除
+	path := []*trieNode[V]{&tr.root}
+	for rest := key; len(rest) > 0; {
+		r, size := nextRune(rest)
+		child, _ := path[len(path)-1].child(r)
+		if child == nil {
+			return false
+		}
+
+		path = append(path, child)
+		rest = rest[size:]
+	}
+
+	n := path[len(path)-1]
+	if !n.hasValue {
+		return false
+	}
+
+	var zero V
+	n.key, n.value, n.hasValue = "", zero, false
+	tr.size--
+
+	for i := len(path) - 1; i > 0 && !path[i].hasValue && len(path[i].children) == 0; i-- {
+		parent := path[i-1]
+		_, j := parent.child(path[i].r)
+		parent.children = append(parent.children[:j], parent.children[j+1:]...)
+	}
+
+	return true
+}
+
+// walk 按键的顺序遍历子树中的键值对, fn返回false时停止遍历
+func (tn *trieNode[V]) walk(fn func(n *trieNode[V]) bool) bool {
+	if tn.hasValue && !fn(tn) {
+		return false
+	}
+
+	for _, child := range tn.children {
+		if !child.walk(fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// KeysWithPrefix 获取所有以prefix开头的键, 按键排序
+func (tr *Trie[V]) KeysWithPrefix(prefix string) []string {
+	n := tr.find(prefix)
+	if n == nil {
+		return nil
+	}
+
+	var keys []string
+	n.walk(func(n *trieNode[V]) bool {
+		keys = append(keys, n.key)
+		return true
+	})
+
+	return keys
+}
+
+// LongestPrefixOf 获取是s的前缀的最长的键
+func (tr *Trie[V]) LongestPrefixOf(s string) (key string, value V, ok bool) {
+	n := &tr.root
+	for {
+		if n.hasValue {
+			key, value, ok = n.key, n.value, true
+		}
+
+		if len(s) == 0 {
+			return
+		}
+
+		r, size := nextRune(s)
+		if n, _ = n.child(r); n == nil {
+			return
+		}
+		s = s[size:]
+	}
+}
+
+// TopK 获取以prefix开头的键中权重最大的k个, 按权重从大到小排序, 权重相同时按键排序
+func (tr *Trie[V]) TopK(prefix string, k int, weight func(key string, value V) float64) []string {
+	n := tr.find(prefix)
+	if n == nil || k <= 0 {
+		return nil
+	}
+
+	type candidate struct {
+		key    string
+		weight float64
+	}
+	// worse 权重小的、权重相同时键大的排在前面
+	worse := func(a, b candidate) bool {
+		if a.weight != b.weight {
+			return a.weight < b.weight
+		}
+		return a.key > b.key
+	}
+
+	// 保留k个最好的候选, 堆顶为其中最差的
+	h := NewHeap(worse)
+	n.walk(func(n *trieNode[V]) bool {
+		c := candidate{key: n.key, weight: weight(n.key, n.value)}
+		if h.Len() < k {
+			h.Push(c)
+		} else if top, _ := h.Peek(); worse(top, c) {
+			h.list[0] = c
+			h.Fix(0)
+		}
+		return true
+	})
+
+	keys := make([]string, h.Len())
+	for i := len(keys) - 1; i >= 0; i-- {
+		c, _ := h.Pop()
+		keys[i] = c.key
+	}
+
+	return keys
+}
+
+// FuzzySearch 获取与key的编辑距离(按字符计算的插入、删除、替换次数)不超过maxDistance的键, 按键排序
+func (tr *Trie[V]) FuzzySearch(key string, maxDistance int) []string {
+	if maxDistance < 0 {
+		return nil
+	}
+
+	var query []rune
+	for rest := key; len(rest) > 0; {
+		r, size := nextRune(rest)
+		query = append(query, r)
+		rest = rest[size:]
+	}
+
+	// row[j] 为当前节点对应的前缀与query[:j]的编辑距离
+	row := make([]int, len(query)+1)
+	for j := range row {
+		row[j] = j
+	}
+
+	var keys []string
+	if tr.root.hasValue && row[len(query)] <= maxDistance {
+		keys = append(keys, tr.root.key)
+	}
+
+	var search func(n *trieNode[V], prev []int)
+	search = func(n *trieNode[V], prev []int) {
+		row := make([]int, len(prev))
+		row[0] = prev[0] + 1
+		best := row[0]
+		for j := 1; j < len(row); j++ {
+			cost := 1
+			if query[j-1] == n.r {
+				cost = 0
+			}
+			row[j] = math.Min(math.Min(prev[j]+1, row[j-1]+1), prev[j-1]+cost)
+			best = math.Min(best, row[j])
+		}
+
+		if n.hasValue && row[len(query)] <= maxDistance {
+			keys = append(keys, n.key)
+		}
+
+		// 这一行的最小值只会随深度增加, 超过阈值后剪枝
+		if best > maxDistance {
+			return
+		}
+		for _, child := range n.children {
+			search(child, row)
+		}
+	}
+
+	for _, child := range tr.root.children {
+		search(child, row)
+	}
+
+	return keys
+}
@@ -0,0 +1,101 @@
+package tree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrie(t *testing.T) {
+	tr := NewTrie[int]()
+	keys := []string{"she", "sells", "sea", "shells", "by", "the", "sea", "shore", "", "茶", "茶杯", "\xff", "\xfe"}
+	for i, key := range keys {
+		tr.Put(key, i)
+	}
+	assert.Equal(t, 12, tr.Len())
+
+	v, ok := tr.Get("sea")
+	assert.True(t, ok)
+	assert.Equal(t, 6, v)
+	v, ok = tr.Get("")
+	assert.True(t, ok)
+	assert.Equal(t, 8, v)
+	_, ok = tr.Get("se")
+	assert.False(t, ok)
+	_, ok = tr.Get("shell")
+	assert.False(t, ok)
+	v, _ = tr.Get("\xfe")
+	assert.Equal(t, 12, v)
+
+	assert.Equal(t, []string{"sea", "sells", "she", "shells", "shore"}, tr.KeysWithPrefix("s"))
+	assert.Equal(t, []string{"茶", "茶杯"}, tr.KeysWithPrefix("茶"))
+	assert.Equal(t, []string{"", "\xfe", "\xff", "by", "sea", "sells", "she", "shells", "shore", "the", "茶", "茶杯"},
+		tr.KeysWithPrefix(""))
+	assert.Nil(t, tr.KeysWithPrefix("x"))
+
+	key, v, ok := tr.LongestPrefixOf("shellsort")
+	assert.True(t, ok)
+	assert.Equal(t, "shells", key)
+	assert.Equal(t, 3, v)
+	key, _, _ = tr.LongestPrefixOf("shell")
+	assert.Equal(t, "she", key)
+	key, _, ok = tr.LongestPrefixOf("xyz")
+	assert.True(t, ok)
+	assert.Equal(t, "", key)
+	key, _, _ = tr.LongestPrefixOf("茶杯子")
+	assert.Equal(t, "茶杯", key)
+
+	assert.True(t, tr.Delete("she"))
+	assert.False(t, tr.Delete("she"))
+	assert.False(t, tr.Delete("sh"))
+	assert.False(t, tr.Delete("shellsort"))
+	assert.True(t, tr.Delete("shells"))
+	assert.Equal(t, []string{"shore"}, tr.KeysWithPrefix("sh"))
+	// 不再有数据的节点被删除
+	s, _ := tr.root.child('s')
+	h, _ := s.child('h')
+	assert.Len(t, h.children, 1)
+	assert.True(t, tr.Delete(""))
+	_, _, ok = tr.LongestPrefixOf("xyz")
+	assert.False(t, ok)
+	assert.Equal(t, 9, tr.Len())
+
+	var zero Trie[string]
+	zero.Put("a", "b")
+	assert.Equal(t, 1, zero.Len())
+}
+
+func TestTrieTopK(t *testing.T) {
+	tr := NewTrie[int]()
+	freq := map[string]int{
+		"go": 50, "golang": 90, "google": 90, "gopher": 30, "gone": 10, "good": 70, "java": 100,
+	}
+	for k, v := range freq {
+		tr.Put(k, v)
+	}
+	weight := func(_ string, v int) float64 {
+		return float64(v)
+	}
+
+	assert.Equal(t, []string{"golang", "google", "good"}, tr.TopK("go", 3, weight))
+	assert.Equal(t, []string{"golang", "google", "good", "go", "gopher", "gone"}, tr.TopK("go", 10, weight))
+	assert.Equal(t, []string{"gopher"}, tr.TopK("gop", 5, weight))
+	assert.Nil(t, tr.TopK("go", 0, weight))
+	assert.Nil(t, tr.TopK("x", 3, weight))
+}
+
+func TestTrieFuzzySearch(t *testing.T) {
+	tr := NewTrie[struct{}]()
+	for _, key := range []string{"", "a", "cat", "cart", "cast", "coat", "dog", "category", "咖啡", "咖喱"} {
+		tr.Put(key, struct{}{})
+	}
+
+	assert.Equal(t, []string{"cat"}, tr.FuzzySearch("cat", 0))
+	assert.Equal(t, []string{"cart", "cast", "cat", "coat"}, tr.FuzzySearch("cat", 1))
+	assert.Equal(t, []string{"a", "cart", "cast", "cat", "coat"}, tr.FuzzySearch("cat", 2))
+	assert.Equal(t, []string{"cast", "cat"}, tr.FuzzySearch("cst", 1))
+	assert.Equal(t, []string{"", "a"}, tr.FuzzySearch("", 1))
+	// 按字符而不是字节计算距离
+	assert.Equal(t, []string{"咖啡", "咖喱"}, tr.FuzzySearch("咖啡", 1))
+	assert.Nil(t, tr.FuzzySearch("cat", -1))
+}